@@ -0,0 +1,462 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	nendberrors "github.com/nen-co/nendb-go/pkg/errors"
+)
+
+// Exit codes shared by every command
+const (
+	exitOK           = 0 // the command succeeded
+	exitError        = 1 // the command failed
+	exitUsage        = 2 // the command line is invalid
+	exitNotFound     = 3 // a requested node or edge does not exist
+	exitUnauthorized = 4 // the server rejected the credentials
+	exitUnavailable  = 5 // the server could not be reached or timed out
+)
+
+// runFunc runs a command with its positional arguments
+type runFunc func(e *env, args []string) error
+
+// command is a node in the command tree. Groups have subcommands; leaves
+// have a setup function that registers their flags and returns the function
+// that runs them.
+type command struct {
+	name    string
+	args    string // positional argument synopsis, such as "<id>..."
+	summary string
+	help    string // description and examples shown by help
+
+	minArgs int
+	maxArgs int // negative for no limit
+
+	setup    func(fs *flag.FlagSet) runFunc
+	commands []*command
+
+	// validArgs are offered by shell completion for positional arguments
+	validArgs []string
+	// local commands never contact a server
+	local  bool
+	hidden bool
+}
+
+// find returns the subcommand with the given name
+func (c *command) find(name string) *command {
+	for _, sub := range c.commands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// env is what a command needs to run
+type env struct {
+	ctx    context.Context
+	out    io.Writer
+	errOut io.Writer
+	conn   *connectionFlags
+	// format is the -o output format, empty when not given
+	format outputFormat
+	// logger is set by -v and -vv
+	logger *slog.Logger
+}
+
+// client creates a client from the connection flags
+func (e *env) client() (*client.NenDBClient, error) {
+	c, err := e.conn.newClient(e.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return c, nil
+}
+
+// status prints a progress message. Messages go to stderr so that stdout
+// only carries results.
+func (e *env) status(format string, a ...interface{}) {
+	fmt.Fprintf(e.errOut, format+"\n", a...)
+}
+
+// outputFormat returns the -o format, or def when it was not given
+func (e *env) outputFormat(def outputFormat) outputFormat {
+	if e.format == "" {
+		return def
+	}
+	return e.format
+}
+
+// print writes a result in the -o format, JSON by default
+func (e *env) print(v interface{}) error {
+	return render(e.out, e.outputFormat(formatJSON), v)
+}
+
+// usageError is an invalid command line. It carries the command path so the
+// error can point at the right help.
+type usageError struct {
+	path string
+	err  error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// reportedError is an error that has already been shown to the user; it
+// sets the exit code without being printed again
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
+
+// usagef reports an invalid command line from inside a command
+func usagef(format string, a ...interface{}) error {
+	return &usageError{err: fmt.Errorf(format, a...)}
+}
+
+// exitCode maps an error returned by a command to the process exit code
+func exitCode(err error) int {
+	var usageErr *usageError
+	var connErr *nendberrors.NenDBConnectionError
+	var timeoutErr *nendberrors.NenDBTimeoutError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.Is(err, nendberrors.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, nendberrors.ErrUnauthorized):
+		return exitUnauthorized
+	case errors.As(err, &connErr), errors.As(err, &timeoutErr), errors.Is(err, context.DeadlineExceeded):
+		return exitUnavailable
+	default:
+		return exitError
+	}
+}
+
+// run executes the command line args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if legacy, ok := legacyArgs(args); ok {
+		fmt.Fprintf(stderr, "Warning: -command is deprecated; use: nendb %s\n", strings.Join(legacy, " "))
+		args = legacy
+	}
+
+	cmd, path, rest, err := resolve(args)
+	if err == nil {
+		err = execute(cmd, path, rest, stdout, stderr)
+	}
+
+	code := exitCode(err)
+	var reported *reportedError
+	if code != exitOK && !errors.As(err, &reported) {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			if usageErr.path == "" {
+				usageErr.path = strings.Join(path, " ")
+			}
+			fmt.Fprintf(stderr, "Run '%s' for usage.\n", strings.TrimSpace("nendb help "+usageErr.path))
+		}
+	}
+	return code
+}
+
+// resolve finds the command named by args. Flags may appear before, between
+// and after the command names; they are returned with the other arguments
+// for the command to parse.
+func resolve(args []string) (*command, []string, []string, error) {
+	global := flag.NewFlagSet("nendb", flag.ContinueOnError)
+	addGlobalFlags(global)
+
+	cmd := rootCommand
+	var path, rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case cmd.setup != nil || arg == "--":
+			return cmd, path, append(rest, args[i:]...), nil
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			rest = append(rest, arg)
+			if takesValue(global, arg) && i+1 < len(args) {
+				i++
+				rest = append(rest, args[i])
+			}
+		default:
+			sub := cmd.find(arg)
+			if sub == nil {
+				return cmd, path, nil, &usageError{path: strings.Join(path, " "), err: fmt.Errorf("unknown command %q", strings.TrimSpace(strings.Join(append(path, arg), " ")))}
+			}
+			cmd = sub
+			path = append(path, arg)
+		}
+	}
+	return cmd, path, rest, nil
+}
+
+// execute parses the flags of cmd and runs it. Groups only accept -help,
+// and the root also accepts -version.
+func execute(cmd *command, path, args []string, stdout, stderr io.Writer) error {
+	name := strings.TrimSpace("nendb " + strings.Join(path, " "))
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	global := addGlobalFlags(fs)
+	e := &env{ctx: context.Background(), out: stdout, errOut: stderr, conn: global.conn}
+
+	if cmd.setup == nil {
+		showVersion := false
+		if cmd == rootCommand {
+			fs.BoolVar(&showVersion, "version", false, "Show version")
+		}
+		if _, err := parseArgs(fs, args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				printHelp(stdout, cmd, path)
+				return nil
+			}
+			return &usageError{path: strings.Join(path, " "), err: err}
+		}
+		if showVersion {
+			return printVersion(e)
+		}
+		if cmd == rootCommand {
+			printHelp(stdout, cmd, path)
+			return nil
+		}
+		return &usageError{path: strings.Join(path, " "), err: fmt.Errorf("%s requires a subcommand", name)}
+	}
+
+	runCmd := cmd.setup(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printHelp(stdout, cmd, path)
+			return nil
+		}
+		return &usageError{path: strings.Join(path, " "), err: err}
+	}
+	switch {
+	case len(positional) < cmd.minArgs:
+		return &usageError{path: strings.Join(path, " "), err: fmt.Errorf("%s requires %s", name, cmd.args)}
+	case cmd.maxArgs >= 0 && len(positional) > cmd.maxArgs:
+		return &usageError{path: strings.Join(path, " "), err: fmt.Errorf("%s: unexpected argument %q", name, positional[cmd.maxArgs])}
+	}
+
+	e.format = global.output
+	e.logger = global.logger(stderr)
+	err = runCmd(e, positional)
+	var usageErr *usageError
+	if errors.As(err, &usageErr) && usageErr.path == "" {
+		usageErr.path = strings.Join(path, " ")
+	}
+	return err
+}
+
+// parseArgs parses flags wherever they appear among the positional
+// arguments, unlike flag.FlagSet.Parse which stops at the first positional
+// argument. Everything after "--" is positional.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// takesValue reports whether the flag argument arg names a flag in fs that
+// consumes the next argument as its value
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if strings.Contains(name, "=") {
+		return false
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return false
+	}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return false
+	}
+	return true
+}
+
+// printHelp writes the help for cmd
+func printHelp(w io.Writer, cmd *command, path []string) {
+	name := strings.TrimSpace("nendb " + strings.Join(path, " "))
+	if cmd == rootCommand {
+		printRootHelp(w)
+		return
+	}
+
+	if cmd.setup == nil {
+		fmt.Fprintf(w, "Usage: %s <command> [flags]\n\n", name)
+	} else {
+		fmt.Fprintf(w, "Usage: %s\n\n", strings.Join(strings.Fields(name+" "+cmd.args+" [flags]"), " "))
+	}
+	if cmd.help != "" {
+		fmt.Fprintf(w, "%s\n", strings.TrimSpace(cmd.help))
+	} else {
+		fmt.Fprintf(w, "%s.\n", cmd.summary)
+	}
+
+	if cmd.setup == nil {
+		fmt.Fprintf(w, "\nCommands:\n")
+		printCommandList(w, cmd)
+		fmt.Fprintf(w, "\nRun 'nendb help %s <command>' for details.\n", strings.Join(path, " "))
+		return
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd.setup(fs)
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(w, "\nFlags:\n")
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+	if !cmd.local {
+		fmt.Fprintf(w, "\nGlobal flags such as -o, -url and -profile are accepted by every command;\nrun 'nendb help' to list them.\n")
+	}
+}
+
+// printCommandList writes the visible subcommands of cmd with their summaries
+func printCommandList(w io.Writer, cmd *command) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, sub := range cmd.commands {
+		if !sub.hidden {
+			fmt.Fprintf(tw, "  %s\t%s\n", sub.name, sub.summary)
+		}
+	}
+	tw.Flush()
+}
+
+func printRootHelp(w io.Writer) {
+	fmt.Fprintf(w, `NenDB Go Driver v%s
+
+Usage: nendb <command> [subcommand] [args...] [flags]
+
+Commands:
+`, version)
+	printCommandList(w, rootCommand)
+	fmt.Fprintf(w, `
+Global flags (accepted by every command, before or after its arguments):
+  -o format          Output format: table, json, yaml, csv or ndjson (default
+                     json); also -output. Progress messages go to stderr.
+  -config file       Profile file, YAML, TOML or JSON (env NENDB_CONFIG)
+  -profile name      Profile to load, e.g. dev or prod (env NENDB_PROFILE)
+  -url string        NenDB server base URL (default "http://localhost:8080")
+  -timeout duration  Request timeout (default 30s)
+  -retries int       Maximum number of retries (default 3)
+  -skip-health       Skip health check on startup
+  -v, -vv            Log requests and retries to stderr; -vv also logs each
+                     attempt with redacted headers and payloads
+
+Authentication and TLS:
+  -api-key string    API key (env NENDB_API_KEY)
+  -api-key-header    Header carrying the API key (default "X-API-Key")
+  -token string      Bearer token (env NENDB_TOKEN)
+  -user string       Basic auth user name (env NENDB_USERNAME)
+  -password string   Basic auth password (env NENDB_PASSWORD)
+  -ca-cert file      PEM CA bundle (env NENDB_CA_CERT)
+  -client-cert file  PEM client certificate for mutual TLS (env NENDB_CLIENT_CERT)
+  -client-key file   PEM client key for mutual TLS (env NENDB_CLIENT_KEY)
+  -insecure          Skip server certificate verification
+
+Settings are layered: defaults, then the profile, then NENDB_* environment
+variables (NENDB_URL, NENDB_TIMEOUT, NENDB_MAX_RETRIES, ...), then flags.
+Flags may be written with one or two dashes.
+
+Exit codes:
+  0  success
+  1  the command failed
+  2  invalid command line
+  3  node or edge not found
+  4  authentication failed
+  5  server unreachable or timed out
+
+Examples:
+  nendb health
+  nendb node create --label Person --prop name=Alice --prop age=30
+  nendb node get 1 -o yaml
+  nendb node list --label Person -o ndjson | jq .properties.name
+  nendb edge create 1 2 --type KNOWS --prop since=2020
+  nendb edge delete 7
+  nendb algo pagerank --iterations 50
+  nendb query "MATCH (n) RETURN n LIMIT 5" --profile prod
+  nendb import -nodes people.csv -label Person -edges knows.csv -type KNOWS
+  nendb export --file graph.graphml
+  nendb shell -profile dev
+
+Run 'nendb help <command>' for details about a command.
+`)
+}
+
+// legacyArgs rewrites the old "nendb [flags] -command <name> [args]" form to
+// the equivalent subcommand, reporting false when -command is not used
+func legacyArgs(args []string) ([]string, bool) {
+	fs := flag.NewFlagSet("nendb", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	addGlobalFlags(fs)
+	name := fs.String("command", "", "")
+	fs.Bool("help", false, "")
+	fs.Bool("version", false, "")
+	if fs.Parse(args) != nil || *name == "" {
+		return nil, false
+	}
+
+	positional := fs.Args()
+	var flags []string
+	consumed := args[:len(args)-len(positional)]
+	for i := 0; i < len(consumed); i++ {
+		switch a := strings.TrimLeft(consumed[i], "-"); {
+		case a == "command":
+			i++
+		case strings.HasPrefix(a, "command="):
+		default:
+			flags = append(flags, consumed[i])
+		}
+	}
+
+	var words []string
+	switch *name {
+	case "node", "edge":
+		words = append([]string{*name, "get"}, positional...)
+	case "algorithm":
+		words = []string{"algo"}
+		if len(positional) > 0 {
+			words = append(words, positional[0])
+			names := map[string][]string{
+				"bfs":      {"", "", "--depth"},
+				"pagerank": {"--iterations", "--tolerance"},
+			}[positional[0]]
+			for i, arg := range positional[1:] {
+				if i < len(names) && names[i] != "" {
+					words = append(words, names[i])
+				}
+				words = append(words, arg)
+			}
+		}
+	default:
+		words = append([]string{*name}, positional...)
+	}
+	return append(words, flags...), true
+}
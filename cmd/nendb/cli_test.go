@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+// runCLI runs the command line against srv and returns the exit code and output
+func runCLI(t *testing.T, srv *nendbtest.Server, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if srv != nil {
+		args = append(args, "--url", srv.URL, "--retries", "0")
+	}
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestNodeCRUD(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	code, out, errOut := runCLI(t, srv, "node", "create", "--label", "Person", "--prop", "name=Alice", "--prop", "age=30", "--prop", `zip="01234"`)
+	if code != exitOK {
+		t.Fatalf("create exited with %d: %s", code, errOut)
+	}
+	var created struct {
+		ID         int                    `json:"id"`
+		Labels     []string               `json:"labels"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", out, err)
+	}
+	want := map[string]interface{}{"name": "Alice", "age": 30.0, "zip": "01234"}
+	if !reflect.DeepEqual(created.Properties, want) || !reflect.DeepEqual(created.Labels, []string{"Person"}) {
+		t.Errorf("Unexpected node %+v", created)
+	}
+
+	code, _, errOut = runCLI(t, srv, "node", "update", "1", "--prop", "age=31", "--unset", "zip", "--label", "Person,Manager")
+	if code != exitOK {
+		t.Fatalf("update exited with %d: %s", code, errOut)
+	}
+	node := srv.Node(created.ID)
+	if !reflect.DeepEqual(node.Labels, []string{"Person", "Manager"}) || node.Properties["zip"] != nil || node.Properties["name"] != "Alice" {
+		t.Errorf("Unexpected node after update %+v", node)
+	}
+
+	if code, _, errOut := runCLI(t, srv, "node", "delete", "1"); code != exitOK {
+		t.Fatalf("delete exited with %d: %s", code, errOut)
+	}
+	code, _, errOut = runCLI(t, srv, "node", "get", "1")
+	if code != exitNotFound || !strings.Contains(errOut, "Error: failed to get node 1") {
+		t.Errorf("Expected not found exit code %d, got %d: %s", exitNotFound, code, errOut)
+	}
+}
+
+func TestEdgeCommandsAndFlagsAfterArguments(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	a := srv.AddNode([]string{"Person"}, nil)
+	b := srv.AddNode([]string{"Person"}, nil)
+
+	// Connection flags come after the positional arguments here
+	code, _, errOut := runCLI(t, srv, "edge", "create", "1", "2", "--type", "KNOWS", "--prop", "since=2020")
+	if code != exitOK {
+		t.Fatalf("create exited with %d: %s", code, errOut)
+	}
+	edges := srv.Edges()
+	if len(edges) != 1 || edges[0].Source != a.ID || edges[0].Target != b.ID || edges[0].Type != "KNOWS" {
+		t.Fatalf("Unexpected edges %+v", edges)
+	}
+
+	if code, _, errOut := runCLI(t, srv, "edge", "update", "1", "--type", "LIKES"); code != exitOK {
+		t.Fatalf("update exited with %d: %s", code, errOut)
+	}
+	if e := srv.Edge(edges[0].ID); e.Type != "LIKES" || e.Properties["since"] == nil {
+		t.Errorf("Expected the type to change and properties to be kept, got %+v", e)
+	}
+
+	code, out, errOut := runCLI(t, srv, "edge", "delete", "1")
+	if code != exitOK || out != "" || errOut != "Deleted edge 1\n" || len(srv.Edges()) != 0 {
+		t.Errorf("Unexpected delete result %d %q %q", code, out, errOut)
+	}
+}
+
+func TestAlgorithmAndLegacyCommands(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	a := srv.AddNode(nil, nil)
+	b := srv.AddNode(nil, nil)
+	srv.AddEdge(a.ID, b.ID, "LINKS", nil)
+
+	code, out, errOut := runCLI(t, srv, "algo", "pagerank", "--iterations", "50")
+	if code != exitOK || !strings.Contains(out, "node_scores") {
+		t.Errorf("pagerank exited with %d: %s%s", code, out, errOut)
+	}
+
+	code, out, errOut = runCLI(t, srv, "-command", "algorithm", "bfs", "1", "2", "3")
+	if code != exitOK || !strings.Contains(out, "visited_nodes") {
+		t.Errorf("legacy bfs exited with %d: %s%s", code, out, errOut)
+	}
+	if !strings.Contains(errOut, "use: nendb algo bfs 1 2 --depth 3") {
+		t.Errorf("Expected a deprecation warning, got %q", errOut)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice", "tags": []interface{}{"a", "b"}})
+	srv.AddNode([]string{"Person", "Admin"}, map[string]interface{}{"name": "yes"})
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", `ID  LABELS        PROPERTIES
+--  ------------  ---------------------------------
+1   Person        {"name":"Alice","tags":["a","b"]}
+2   Person,Admin  {"name":"yes"}
+(2 rows)
+`},
+		{"csv", `id,labels,properties
+1,Person,"{""name"":""Alice"",""tags"":[""a"",""b""]}"
+2,"Person,Admin","{""name"":""yes""}"
+`},
+		{"ndjson", `{"id":1,"labels":["Person"],"properties":{"name":"Alice","tags":["a","b"]}}
+{"id":2,"labels":["Person","Admin"],"properties":{"name":"yes"}}
+`},
+		{"yaml", `- id: 1
+  labels:
+  - Person
+  properties:
+    name: Alice
+    tags:
+    - a
+    - b
+- id: 2
+  labels:
+  - Person
+  - Admin
+  properties:
+    name: "yes"
+`},
+	}
+	for _, tt := range tests {
+		code, out, errOut := runCLI(t, srv, "node", "list", "--output", tt.format)
+		if code != exitOK {
+			t.Fatalf("%s: exited with %d: %s", tt.format, code, errOut)
+		}
+		if out != tt.want {
+			t.Errorf("%s output:\n%s\nwant:\n%s", tt.format, out, tt.want)
+		}
+	}
+
+	// Progress messages stay off stdout so it can be piped
+	code, out, errOut := runCLI(t, srv, "-o", "json", "stats")
+	var stats map[string]interface{}
+	if code != exitOK || json.Unmarshal([]byte(out), &stats) != nil || !strings.Contains(errOut, "Getting database statistics") {
+		t.Errorf("Expected only JSON on stdout, got %q and %q", out, errOut)
+	}
+
+	if code, _, errOut := runCLI(t, nil, "stats", "-o", "xml"); code != exitUsage || !strings.Contains(errOut, `unknown output format "xml"`) {
+		t.Errorf("Expected a usage error for an unknown format, got %d %q", code, errOut)
+	}
+}
+
+func TestYAMLQuoting(t *testing.T) {
+	var b bytes.Buffer
+	v := map[string]interface{}{
+		"plain": "Alice", "bool": "true", "number": "42", "empty": "", "colon": "a: b",
+		"multi": "a\nb", "list": []interface{}{}, "nested": map[string]interface{}{}, "null": nil,
+	}
+	if err := writeYAML(&b, v); err != nil {
+		t.Fatal(err)
+	}
+	want := `bool: "true"
+colon: "a: b"
+empty: ""
+list: []
+multi: "a\nb"
+nested: {}
+"null": null
+number: "42"
+plain: Alice
+`
+	if b.String() != want {
+		t.Errorf("writeYAML:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"nodes"}, `unknown command "nodes"`},
+		{[]string{"node"}, "nendb node requires a subcommand"},
+		{[]string{"node", "get"}, "nendb node get requires <id>..."},
+		{[]string{"node", "get", "x"}, "invalid node ID: x"},
+		{[]string{"node", "create", "--prop", "novalue"}, `invalid property "novalue"`},
+		{[]string{"edge", "create", "1", "2"}, "--type is required"},
+		{[]string{"stats", "extra"}, `unexpected argument "extra"`},
+		{[]string{"stats", "--bogus"}, "flag provided but not defined: -bogus"},
+		{[]string{"completion", "tcsh"}, `unsupported shell "tcsh"`},
+	}
+	for _, tt := range tests {
+		code, _, errOut := runCLI(t, nil, tt.args...)
+		if code != exitUsage || !strings.Contains(errOut, tt.want) {
+			t.Errorf("%v: got exit code %d and %q, want %d and %q", tt.args, code, errOut, exitUsage, tt.want)
+		}
+	}
+
+	code, out, _ := runCLI(t, nil, "node", "update", "--help")
+	if code != exitOK || !strings.Contains(out, "Usage: nendb node update <id> [flags]") || !strings.Contains(out, "-unset") {
+		t.Errorf("Unexpected help output %d %q", code, out)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{nil, []string{"health", "node", "edge", "algo", "query", "stats", "import", "export", "shell", "completion", "help", "version"}},
+		{[]string{"--url", "http://x", "algo"}, []string{"bfs", "dijkstra", "pagerank"}},
+		{[]string{"algo", "pagerank"}, []string{"--iterations", "--tolerance"}},
+		{[]string{"completion"}, []string{"bash", "zsh", "fish"}},
+		{[]string{"node", "get", "-o"}, []string{"table", "json", "yaml", "csv", "ndjson"}},
+		{[]string{"import", "-nodes"}, nil},
+	}
+	for _, tt := range tests {
+		if got := completions(tt.words); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completions(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+
+	for _, shell := range completionShells {
+		code, out, _ := runCLI(t, nil, "completion", shell)
+		if code != exitOK || !strings.Contains(out, completeCommand+" --") {
+			t.Errorf("completion %s: unexpected script %q", shell, out)
+		}
+	}
+}
+
+func TestCommandFlagsDoNotClashWithGlobalFlags(t *testing.T) {
+	var walk func(cmd *command, path string)
+	walk = func(cmd *command, path string) {
+		for _, sub := range cmd.commands {
+			walk(sub, strings.TrimSpace(path+" "+sub.name))
+		}
+		if cmd.setup == nil {
+			return
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("%s: %v", path, r)
+			}
+		}()
+		fs := flag.NewFlagSet(path, flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		addGlobalFlags(fs)
+		cmd.setup(fs)
+	}
+	walk(rootCommand, "")
+}
+
+func TestVerboseLogging(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	code, out, errOut := runCLI(t, srv, "node", "create", "--prop", "name=Alice", "-v", "--api-key", "s3cret")
+	if code != exitOK || out == "" {
+		t.Fatalf("create exited with %d: %s", code, errOut)
+	}
+	if !strings.Contains(errOut, `msg="nendb request completed" operation=CreateNode`) || strings.Contains(errOut, "nendb attempt") {
+		t.Errorf("Expected -v to log completed requests only, got %q", errOut)
+	}
+
+	code, _, errOut = runCLI(t, srv, "-vv", "node", "create", "--prop", "name=Alice", "--api-key", "s3cret")
+	if code != exitOK || !strings.Contains(errOut, "nendb attempt") || !strings.Contains(errOut, "[REDACTED]") {
+		t.Errorf("Expected -vv to log attempts with redacted payloads, got %q", errOut)
+	}
+	if strings.Contains(errOut, "Alice") || strings.Contains(errOut, "s3cret") {
+		t.Errorf("Expected property values and credentials to be redacted, got %q", errOut)
+	}
+
+	if _, _, errOut := runCLI(t, srv, "stats"); strings.Contains(errOut, "nendb request") {
+		t.Errorf("Expected no request logs without -v, got %q", errOut)
+	}
+}
+
+func TestProfileFileFromEnvironment(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("default:\n  url: "+srv.URL+"\n  max_retries: 0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NENDB_CONFIG", path)
+	t.Setenv("NENDB_PROFILE", "")
+
+	// NENDB_CONFIG, like -config, loads the default profile when none is named
+	if code, out, errOut := runCLI(t, nil, "stats"); code != exitOK || out == "" {
+		t.Fatalf("stats exited with %d: %s", code, errOut)
+	}
+
+	if err := os.WriteFile(path, []byte("prod:\n  url: "+srv.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, errOut := runCLI(t, nil, "stats"); code == exitOK || !strings.Contains(errOut, `"default" not found`) {
+		t.Errorf("Expected a missing default profile to fail, got %d: %s", code, errOut)
+	}
+}
+
+func TestImportNodesWithoutKeyColumn(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	if err := os.WriteFile(path, []byte("name,age\nAlice,30\nBob,25\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, errOut := runCLI(t, srv, "import", "-nodes", path, "-labels", "Person", "-quiet"); code != exitOK {
+		t.Fatalf("import exited with %d: %s", code, errOut)
+	}
+	if len(srv.Nodes()) != 2 {
+		t.Errorf("Expected 2 nodes, got %d", len(srv.Nodes()))
+	}
+
+	// An explicit -key is required in every row, and failed rows fail the
+	// command
+	if code, _, errOut := runCLI(t, srv, "import", "-nodes", path, "-key", "id", "-quiet"); code != exitError || !strings.Contains(errOut, `missing key column "id"`) {
+		t.Errorf("Expected rows without the key to fail, got %d: %s", code, errOut)
+	}
+}
+
+func TestImportLabelFlags(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "people.jsonl")
+	if err := os.WriteFile(path, []byte(`{"name": "Alice"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// --label repeats like it does for node create; -labels is kept as an alias
+	if code, _, errOut := runCLI(t, srv, "import", "-nodes", path, "--label", "Person", "--label", "Admin", "-labels", "Staff,Remote", "-quiet"); code != exitOK {
+		t.Fatalf("import exited with %d: %s", code, errOut)
+	}
+	nodes := srv.Nodes()
+	if want := []string{"Person", "Admin", "Staff", "Remote"}; len(nodes) != 1 || !reflect.DeepEqual(nodes[0].Labels, want) {
+		t.Errorf("Expected one node labelled %v, got %+v", want, nodes)
+	}
+}
@@ -0,0 +1,637 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+const version = "0.1.0"
+
+// rootCommand is the command tree. It is assigned in init because the help
+// and completion commands refer back to it.
+var rootCommand *command
+
+func init() {
+	rootCommand = &command{
+		name: "nendb",
+		commands: []*command{
+			{name: "health", summary: "Check server health", setup: setupHealth},
+			{name: "node", summary: "Create, read, update and delete nodes", commands: []*command{
+				{name: "get", args: "<id>...", summary: "Get nodes by ID", minArgs: 1, maxArgs: -1, setup: setupNodeGet},
+				{name: "list", summary: "List nodes", setup: setupNodeList},
+				{name: "create", summary: "Create a node", setup: setupNodeCreate, help: nodeCreateHelp},
+				{name: "update", args: "<id>", summary: "Update a node", minArgs: 1, maxArgs: 1, setup: setupNodeUpdate, help: nodeUpdateHelp},
+				{name: "delete", args: "<id>...", summary: "Delete nodes by ID", minArgs: 1, maxArgs: -1, setup: setupNodeDelete},
+			}},
+			{name: "edge", summary: "Create, read, update and delete edges", commands: []*command{
+				{name: "get", args: "<id>...", summary: "Get edges by ID", minArgs: 1, maxArgs: -1, setup: setupEdgeGet},
+				{name: "list", summary: "List edges", setup: setupEdgeList},
+				{name: "create", args: "<source> <target>", summary: "Create an edge", minArgs: 2, maxArgs: 2, setup: setupEdgeCreate, help: edgeCreateHelp},
+				{name: "update", args: "<id>", summary: "Update an edge", minArgs: 1, maxArgs: 1, setup: setupEdgeUpdate, help: edgeUpdateHelp},
+				{name: "delete", args: "<id>...", summary: "Delete edges by ID", minArgs: 1, maxArgs: -1, setup: setupEdgeDelete},
+			}},
+			{name: "algo", summary: "Run graph algorithms", commands: []*command{
+				{name: "bfs", args: "<start> <target>", summary: "Breadth-first search between two nodes", minArgs: 2, maxArgs: 2, setup: setupBFS},
+				{name: "dijkstra", args: "<start> <target>", summary: "Shortest weighted path between two nodes", minArgs: 2, maxArgs: 2, setup: setupDijkstra},
+				{name: "pagerank", summary: "Rank nodes with PageRank", setup: setupPageRank},
+			}},
+			{name: "query", args: "<query>", summary: "Execute a query", minArgs: 1, maxArgs: 1, setup: setupQuery, help: queryHelp},
+			{name: "stats", summary: "Get database statistics", setup: setupStats},
+			{name: "import", summary: "Bulk import nodes and edges from CSV or JSON Lines", setup: setupImport, help: importHelp},
+			{name: "export", summary: "Export the graph as GraphML, GEXF or DOT", setup: setupExport, help: exportHelp},
+			{name: "shell", summary: "Interactive shell with history and tab completion", setup: setupShell, help: shellHelp},
+			{name: "completion", args: "<bash|zsh|fish>", summary: "Print a shell completion script", minArgs: 1, maxArgs: 1, setup: setupCompletion, help: completionHelp, validArgs: completionShells, local: true},
+			{name: "help", args: "[command]...", summary: "Show help for a command", maxArgs: -1, setup: setupHelp, local: true},
+			{name: "version", summary: "Show version", setup: func(*flag.FlagSet) runFunc { return runVersion }, local: true},
+			{name: completeCommand, maxArgs: -1, setup: setupComplete, local: true, hidden: true},
+		},
+	}
+}
+
+const (
+	nodeCreateHelp = `Creates a node and prints it.
+
+Properties are given as --prop key=value. Values that look like numbers,
+booleans or null are stored as such; values starting with ", [ or { are
+parsed as JSON, so --prop 'zip="01234"' stores a string and
+--prop 'tags=["a","b"]' a list. --props takes a JSON object, which may use
+typed values such as {"born": {"$time": "1990-01-02T00:00:00Z"}}.
+
+Examples:
+  nendb node create --label Person --prop name=Alice --prop age=30
+  nendb node create --label Person,Employee --props '{"name": "Bob"}'`
+
+	nodeUpdateHelp = `Updates a node and prints it. Given properties are merged into the existing
+ones unless --replace is set; --label replaces the labels.
+
+Examples:
+  nendb node update 1 --prop age=31 --unset nickname
+  nendb node update 1 --label Person,Manager
+  nendb node update 1 --replace --prop name=Alice`
+
+	edgeCreateHelp = `Creates an edge from <source> to <target> and prints it. Properties are
+given as for node create.
+
+Examples:
+  nendb edge create 1 2 --type KNOWS --prop since=2020`
+
+	edgeUpdateHelp = `Updates an edge and prints it. Given properties are merged into the existing
+ones unless --replace is set; --type changes the edge type.
+
+Examples:
+  nendb edge update 7 --prop weight=0.5
+  nendb edge update 7 --type WORKS_WITH`
+
+	queryHelp = `Executes a query and prints the result. Parameters are given as
+--param key=value, with values interpreted as for --prop.
+
+Examples:
+  nendb query "MATCH (n:Person) RETURN n LIMIT 5"
+  nendb query "MATCH (n) WHERE n.name = $name RETURN n" --param name=Alice`
+)
+
+func printVersion(e *env) error {
+	fmt.Fprintf(e.out, "nendb-go-driver version %s\n", version)
+	return nil
+}
+
+func runVersion(e *env, args []string) error {
+	return printVersion(e)
+}
+
+func setupHelp(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		cmd := rootCommand
+		for _, name := range args {
+			if cmd = cmd.find(name); cmd == nil {
+				return usagef("unknown command %q", strings.Join(args, " "))
+			}
+		}
+		printHelp(e.out, cmd, args)
+		return nil
+	}
+}
+
+func setupHealth(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		e.status("Checking NenDB server health...")
+		if err := c.Health(); err != nil {
+			return fmt.Errorf("health check failed: %w", err)
+		}
+		e.status("✓ Server is healthy")
+		return nil
+	}
+}
+
+func setupNodeGet(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		ids, err := parseIDs("node", args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		var nodes []types.GraphNode
+		for _, id := range ids {
+			node, err := c.GetNode(e.ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get node %d: %w", id, err)
+			}
+			nodes = append(nodes, *node)
+		}
+		if len(nodes) == 1 {
+			return e.print(&nodes[0])
+		}
+		return e.print(nodes)
+	}
+}
+
+func setupNodeList(fs *flag.FlagSet) runFunc {
+	label := fs.String("label", "", "Only list nodes with this label")
+	limit := fs.Int("limit", 100, "Maximum number of nodes; 0 lists all")
+	return func(e *env, args []string) error {
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		nodes := []types.GraphNode{}
+		for node, err := range c.Nodes(e.ctx, &client.NodeListOptions{Label: *label}) {
+			if err != nil {
+				return fmt.Errorf("failed to list nodes: %w", err)
+			}
+			nodes = append(nodes, *node)
+			if *limit > 0 && len(nodes) == *limit {
+				break
+			}
+		}
+		return e.print(nodes)
+	}
+}
+
+func setupNodeCreate(fs *flag.FlagSet) runFunc {
+	labels := addListFlag(fs, "label", "Node label; repeat or separate with commas")
+	props := addPropertyFlags(fs)
+	return func(e *env, args []string) error {
+		properties, err := props.properties(nil)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		node, err := c.CreateNode(e.ctx, *labels, properties)
+		if err != nil {
+			return fmt.Errorf("failed to create node: %w", err)
+		}
+		return e.print(node)
+	}
+}
+
+func setupNodeUpdate(fs *flag.FlagSet) runFunc {
+	labels := addListFlag(fs, "label", "Replace the labels; repeat or separate with commas")
+	props := addPropertyFlags(fs)
+	props.addUpdateFlags(fs)
+	return func(e *env, args []string) error {
+		id, err := parseID("node", args[0])
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		node, err := c.GetNode(e.ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get node %d: %w", id, err)
+		}
+		properties, err := props.properties(node.Properties)
+		if err != nil {
+			return err
+		}
+		newLabels := node.Labels
+		if len(*labels) > 0 {
+			newLabels = *labels
+		}
+
+		updated, err := c.UpdateNode(e.ctx, id, newLabels, properties)
+		if err != nil {
+			return fmt.Errorf("failed to update node %d: %w", id, err)
+		}
+		return e.print(updated)
+	}
+}
+
+func setupNodeDelete(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		ids, err := parseIDs("node", args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := c.DeleteNode(e.ctx, id); err != nil {
+				return fmt.Errorf("failed to delete node %d: %w", id, err)
+			}
+			e.status("Deleted node %d", id)
+		}
+		return nil
+	}
+}
+
+func setupEdgeGet(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		ids, err := parseIDs("edge", args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		var edges []types.GraphEdge
+		for _, id := range ids {
+			edge, err := c.GetEdge(e.ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get edge %d: %w", id, err)
+			}
+			edges = append(edges, *edge)
+		}
+		if len(edges) == 1 {
+			return e.print(&edges[0])
+		}
+		return e.print(edges)
+	}
+}
+
+func setupEdgeList(fs *flag.FlagSet) runFunc {
+	edgeType := fs.String("type", "", "Only list edges of this type")
+	limit := fs.Int("limit", 100, "Maximum number of edges; 0 lists all")
+	return func(e *env, args []string) error {
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		edges := []types.GraphEdge{}
+		for edge, err := range c.Edges(e.ctx, &client.EdgeListOptions{Type: *edgeType}) {
+			if err != nil {
+				return fmt.Errorf("failed to list edges: %w", err)
+			}
+			edges = append(edges, *edge)
+			if *limit > 0 && len(edges) == *limit {
+				break
+			}
+		}
+		return e.print(edges)
+	}
+}
+
+func setupEdgeCreate(fs *flag.FlagSet) runFunc {
+	edgeType := fs.String("type", "", "Edge type (required)")
+	props := addPropertyFlags(fs)
+	return func(e *env, args []string) error {
+		source, err := parseID("source node", args[0])
+		if err != nil {
+			return err
+		}
+		target, err := parseID("target node", args[1])
+		if err != nil {
+			return err
+		}
+		if *edgeType == "" {
+			return usagef("--type is required")
+		}
+		properties, err := props.properties(nil)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		edge, err := c.CreateEdge(e.ctx, source, target, *edgeType, properties)
+		if err != nil {
+			return fmt.Errorf("failed to create edge: %w", err)
+		}
+		return e.print(edge)
+	}
+}
+
+func setupEdgeUpdate(fs *flag.FlagSet) runFunc {
+	edgeType := fs.String("type", "", "Change the edge type")
+	props := addPropertyFlags(fs)
+	props.addUpdateFlags(fs)
+	return func(e *env, args []string) error {
+		id, err := parseID("edge", args[0])
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		edge, err := c.GetEdge(e.ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get edge %d: %w", id, err)
+		}
+		properties, err := props.properties(edge.Properties)
+		if err != nil {
+			return err
+		}
+		newType := edge.Type
+		if *edgeType != "" {
+			newType = *edgeType
+		}
+
+		updated, err := c.UpdateEdge(e.ctx, id, newType, properties)
+		if err != nil {
+			return fmt.Errorf("failed to update edge %d: %w", id, err)
+		}
+		return e.print(updated)
+	}
+}
+
+func setupEdgeDelete(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		ids, err := parseIDs("edge", args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := c.DeleteEdge(e.ctx, id); err != nil {
+				return fmt.Errorf("failed to delete edge %d: %w", id, err)
+			}
+			e.status("Deleted edge %d", id)
+		}
+		return nil
+	}
+}
+
+func setupBFS(fs *flag.FlagSet) runFunc {
+	depth := fs.Int("depth", 10, "Maximum search depth")
+	return func(e *env, args []string) error {
+		start, target, err := parseEndpoints(args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		e.status("Running bfs algorithm...")
+		result, err := c.RunBFS(e.ctx, start, target, *depth)
+		if err != nil {
+			return fmt.Errorf("bfs algorithm failed: %w", err)
+		}
+		return e.print(result)
+	}
+}
+
+func setupDijkstra(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		start, target, err := parseEndpoints(args)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		e.status("Running dijkstra algorithm...")
+		result, err := c.RunDijkstra(e.ctx, start, target)
+		if err != nil {
+			return fmt.Errorf("dijkstra algorithm failed: %w", err)
+		}
+		return e.print(result)
+	}
+}
+
+func setupPageRank(fs *flag.FlagSet) runFunc {
+	iterations := fs.Int("iterations", 100, "Maximum number of iterations")
+	tolerance := fs.Float64("tolerance", 0.001, "Convergence tolerance")
+	return func(e *env, args []string) error {
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		e.status("Running pagerank algorithm...")
+		result, err := c.RunPageRank(e.ctx, *iterations, *tolerance)
+		if err != nil {
+			return fmt.Errorf("pagerank algorithm failed: %w", err)
+		}
+		return e.print(result)
+	}
+}
+
+func setupQuery(fs *flag.FlagSet) runFunc {
+	params := addValueFlags(fs, "param", "Query parameter as key=value; repeatable", "params", "Query parameters as a JSON object")
+	return func(e *env, args []string) error {
+		values, err := params.properties(nil)
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		e.status("Executing query: %s", args[0])
+		result, err := c.Query(e.ctx, args[0], values)
+		if err != nil {
+			return fmt.Errorf("query failed: %w", err)
+		}
+		return e.print(result)
+	}
+}
+
+func setupStats(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		e.status("Getting database statistics...")
+		stats, err := c.GetStatistics(e.ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get statistics: %w", err)
+		}
+		return e.print(stats)
+	}
+}
+
+// parseID parses a node or edge ID given on the command line
+func parseID(what, s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id < 0 {
+		return 0, usagef("invalid %s ID: %s", what, s)
+	}
+	return id, nil
+}
+
+func parseIDs(what string, args []string) ([]int, error) {
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := parseID(what, arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// parseEndpoints parses the start and target node IDs of a path algorithm
+func parseEndpoints(args []string) (int, int, error) {
+	start, err := parseID("start node", args[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	target, err := parseID("target node", args[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, target, nil
+}
+
+// listFlag is a repeatable flag whose values may also be comma-separated
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, splitList(value)...)
+	return nil
+}
+
+func addListFlag(fs *flag.FlagSet, name, usage string) *[]string {
+	var l listFlag
+	fs.Var(&l, name, usage)
+	return (*[]string)(&l)
+}
+
+// propertyFlags are the flags that set properties on nodes and edges
+type propertyFlags struct {
+	props   []string
+	json    string
+	unset   *[]string
+	replace bool
+}
+
+func addPropertyFlags(fs *flag.FlagSet) *propertyFlags {
+	return addValueFlags(fs, "prop", "Property as key=value; repeatable", "props", "Properties as a JSON object")
+}
+
+// addUpdateFlags registers the flags that only apply when updating
+func (p *propertyFlags) addUpdateFlags(fs *flag.FlagSet) {
+	p.unset = addListFlag(fs, "unset", "Remove a property; repeat or separate with commas")
+	fs.BoolVar(&p.replace, "replace", false, "Replace all properties instead of merging")
+}
+
+// addValueFlags registers a repeatable key=value flag and a JSON object flag
+func addValueFlags(fs *flag.FlagSet, single, singleUsage, object, objectUsage string) *propertyFlags {
+	p := &propertyFlags{}
+	fs.Func(single, singleUsage, func(s string) error {
+		p.props = append(p.props, s)
+		return nil
+	})
+	fs.StringVar(&p.json, object, "", objectUsage)
+	return p
+}
+
+// properties applies the flags to existing, which may be nil
+func (p *propertyFlags) properties(existing types.Properties) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if !p.replace {
+		for k, v := range existing {
+			result[k] = v
+		}
+	}
+
+	if p.json != "" {
+		var object types.Properties
+		if err := json.Unmarshal([]byte(p.json), &object); err != nil {
+			return nil, usagef("invalid JSON object %s: %v", p.json, err)
+		}
+		for k, v := range object {
+			result[k] = v
+		}
+	}
+	for _, prop := range p.props {
+		key, raw, ok := strings.Cut(prop, "=")
+		if !ok || key == "" {
+			return nil, usagef("invalid property %q: want key=value", prop)
+		}
+		value, err := parsePropertyValue(raw)
+		if err != nil {
+			return nil, usagef("invalid value for property %s: %v", key, err)
+		}
+		result[key] = value
+	}
+	if p.unset != nil {
+		for _, key := range *p.unset {
+			delete(result, key)
+		}
+	}
+	return result, nil
+}
+
+// parsePropertyValue converts a value given on the command line. JSON
+// strings, lists and objects are decoded; other values become a bool,
+// integer, float or null when they parse as one and a string otherwise.
+func parsePropertyValue(s string) (interface{}, error) {
+	if s != "" && strings.ContainsRune(`"[{`, rune(s[0])) {
+		var wrapper types.Properties
+		if err := json.Unmarshal([]byte(`{"v":`+s+`}`), &wrapper); err != nil {
+			return nil, err
+		}
+		return wrapper["v"], nil
+	}
+	switch s {
+	case "true", "false":
+		return s == "true", nil
+	case "null":
+		return nil, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	// Words such as "Inf" and "NaN" parse as floats but are meant as text
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f, nil
+	}
+	return s, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// completeCommand is the hidden command the completion scripts call with the
+// words typed so far; it prints one candidate per line
+const completeCommand = "__complete"
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+const completionHelp = `Prints a script that completes commands, subcommands, flags and arguments.
+
+Setup:
+  bash  source <(nendb completion bash)                 # in ~/.bashrc
+  zsh   source <(nendb completion zsh)                  # in ~/.zshrc, after compinit
+  fish  nendb completion fish > ~/.config/fish/completions/nendb.fish`
+
+// completionScripts delegate to the hidden completion command, so they stay
+// in step with the command tree
+var completionScripts = map[string]string{
+	"bash": `# bash completion for nendb
+_nendb() {
+	local IFS=$'\n'
+	local cur=${COMP_WORDS[COMP_CWORD]}
+	COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" ` + completeCommand + ` -- "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F _nendb nendb
+`,
+	"zsh": `#compdef nendb
+# zsh completion for nendb
+_nendb() {
+	local -a candidates
+	candidates=("${(@f)$(${words[1]} ` + completeCommand + ` -- ${words[2,CURRENT-1]} 2>/dev/null)}")
+	if [[ -n ${candidates[1]} ]]; then
+		compadd -a candidates
+	else
+		_files
+	fi
+}
+compdef _nendb nendb
+`,
+	"fish": `# fish completion for nendb
+function __nendb_complete
+	set -l words (commandline -opc)
+	$words[1] ` + completeCommand + ` -- $words[2..-1] 2>/dev/null
+end
+complete -c nendb -a '(__nendb_complete)'
+`,
+}
+
+func setupCompletion(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		script, ok := completionScripts[args[0]]
+		if !ok {
+			return usagef("unsupported shell %q (want %s)", args[0], strings.Join(completionShells, ", "))
+		}
+		_, err := fmt.Fprint(e.out, script)
+		return err
+	}
+}
+
+func setupComplete(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		for _, candidate := range completions(args) {
+			fmt.Fprintln(e.out, candidate)
+		}
+		return nil
+	}
+}
+
+// completions returns the candidates for the word following words: the
+// subcommands of a group, or the arguments and flags of a command. It
+// returns nothing after a flag that takes a value, so the shell falls back
+// to completing file names.
+func completions(words []string) []string {
+	cmd := rootCommand
+	fs := flag.NewFlagSet("nendb", flag.ContinueOnError)
+	addGlobalFlags(fs)
+	positional := 0
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case strings.HasPrefix(word, "-") && word != "-":
+			if takesValue(fs, word) {
+				if i == len(words)-1 {
+					return flagValues(word)
+				}
+				i++
+			}
+		case cmd.setup == nil:
+			if cmd = cmd.find(word); cmd == nil {
+				return nil
+			}
+			if cmd.setup != nil {
+				fs = flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+				addGlobalFlags(fs)
+				cmd.setup(fs)
+			}
+		default:
+			positional++
+		}
+	}
+
+	var candidates []string
+	if cmd.setup == nil {
+		for _, sub := range cmd.commands {
+			if !sub.hidden {
+				candidates = append(candidates, sub.name)
+			}
+		}
+		return candidates
+	}
+
+	if cmd.maxArgs < 0 || positional < cmd.maxArgs {
+		candidates = append(candidates, cmd.validArgs...)
+	}
+	// Offer the command's own flags; the global flags are shared by every
+	// command and would drown them out
+	own := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	cmd.setup(own)
+	own.VisitAll(func(f *flag.Flag) {
+		candidates = append(candidates, "--"+f.Name)
+	})
+	return candidates
+}
+
+// flagValues returns the candidates for the value of a global flag
+func flagValues(arg string) []string {
+	switch strings.TrimLeft(arg, "-") {
+	case "o", "output":
+		return strings.Split(formatNames(" "), " ")
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nen-co/nendb-go/pkg/exporter"
+)
+
+const exportHelp = `Writes the graph stored in NenDB as GraphML, GEXF or Graphviz DOT.
+
+Examples:
+  nendb export -file graph.graphml
+  nendb export -format dot -label Person | dot -Tsvg > people.svg
+  nendb export -file knows.gexf -type KNOWS`
+
+func setupExport(fs *flag.FlagSet) runFunc {
+	var (
+		file     = fs.String("file", "", "Output file (default: stdout)")
+		format   = fs.String("format", "", "Graph format (graphml, gexf, dot); inferred from -file by default")
+		label    = fs.String("label", "", "Only export nodes with this label")
+		edgeType = fs.String("type", "", "Only export edges of this type")
+	)
+	return func(e *env, args []string) error {
+		exportFormat := exporter.Format(*format)
+		if exportFormat == "" {
+			if *file == "" {
+				return usagef("-format is required when writing to stdout")
+			}
+			var err error
+			if exportFormat, err = exporter.FormatFromPath(*file); err != nil {
+				return usagef("%v", err)
+			}
+		}
+
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		g, err := exporter.Fetch(e.ctx, c, exporter.Options{Label: *label, EdgeType: *edgeType})
+		if err != nil {
+			return fmt.Errorf("failed to read graph: %w", err)
+		}
+
+		w := e.out
+		if *file != "" {
+			f, err := os.Create(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if err := exporter.Write(w, g, exportFormat); err != nil {
+			return fmt.Errorf("failed to write %s: %w", exportFormat, err)
+		}
+		fmt.Fprintf(e.errOut, "Exported %d nodes and %d edges\n", len(g.Nodes), len(g.Edges))
+		return nil
+	}
+}
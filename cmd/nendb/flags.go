@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+)
+
+// globalFlags are the flags accepted by every command
+type globalFlags struct {
+	conn *connectionFlags
+	// output is empty unless -o was given
+	output outputFormat
+	// verbose and veryVerbose are -v and -vv
+	verbose     *bool
+	veryVerbose *bool
+}
+
+// addGlobalFlags registers the connection flags and -o/-output on fs
+func addGlobalFlags(fs *flag.FlagSet) *globalFlags {
+	g := &globalFlags{conn: addConnectionFlags(fs)}
+	usage := "Output format: " + formatNames(", ") + " (default json)"
+	fs.Var(&g.output, "o", usage)
+	fs.Var(&g.output, "output", usage)
+	g.verbose = fs.Bool("v", false, "Log requests, retries and failures to stderr")
+	g.veryVerbose = fs.Bool("vv", false, "Also log each attempt with its redacted headers and payload")
+	return g
+}
+
+// logger returns a logger writing to w at the level selected by -v or -vv,
+// or nil when neither was given
+func (g *globalFlags) logger(w io.Writer) *slog.Logger {
+	level := slog.LevelInfo
+	switch {
+	case *g.veryVerbose:
+		level = slog.LevelDebug
+	case !*g.verbose:
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// connectionFlags holds the flags shared by every command that talks to a server
+type connectionFlags struct {
+	fs *flag.FlagSet
+
+	configFile *string
+	profile    *string
+
+	baseURL    *string
+	timeout    *time.Duration
+	maxRetries *int
+	skipHealth *bool
+
+	apiKey       *string
+	apiKeyHeader *string
+	token        *string
+	username     *string
+	password     *string
+
+	caCert     *string
+	clientCert *string
+	clientKey  *string
+	insecure   *bool
+}
+
+// addConnectionFlags registers the connection flags on fs. Settings are
+// layered: client defaults, then the selected profile, then NENDB_*
+// environment variables, then flags given on the command line.
+func addConnectionFlags(fs *flag.FlagSet) *connectionFlags {
+	return &connectionFlags{
+		fs: fs,
+
+		configFile: fs.String("config", "", "Profile file (YAML, TOML or JSON; env NENDB_CONFIG)"),
+		profile:    fs.String("profile", "", "Profile to load from the profile file (env NENDB_PROFILE)"),
+
+		baseURL:    fs.String("url", "http://localhost:8080", "NenDB server base URL"),
+		timeout:    fs.Duration("timeout", 30*time.Second, "Request timeout"),
+		maxRetries: fs.Int("retries", 3, "Maximum number of retries"),
+		skipHealth: fs.Bool("skip-health", false, "Skip health check on startup"),
+
+		apiKey:       fs.String("api-key", "", "API key sent with every request (env NENDB_API_KEY)"),
+		apiKeyHeader: fs.String("api-key-header", client.DefaultAPIKeyHeader, "Header carrying the API key"),
+		token:        fs.String("token", "", "Bearer token (env NENDB_TOKEN)"),
+		username:     fs.String("user", "", "HTTP basic auth user name (env NENDB_USERNAME)"),
+		password:     fs.String("password", "", "HTTP basic auth password (env NENDB_PASSWORD)"),
+
+		caCert:     fs.String("ca-cert", "", "PEM CA bundle used to verify the server (env NENDB_CA_CERT)"),
+		clientCert: fs.String("client-cert", "", "PEM client certificate for mutual TLS (env NENDB_CLIENT_CERT)"),
+		clientKey:  fs.String("client-key", "", "PEM client key for mutual TLS (env NENDB_CLIENT_KEY)"),
+		insecure:   fs.Bool("insecure", false, "Skip server certificate verification"),
+	}
+}
+
+// newClient creates a client from the layered configuration. logger may be
+// nil.
+func (f *connectionFlags) newClient(logger *slog.Logger) (*client.NenDBClient, error) {
+	config, err := f.config()
+	if err != nil {
+		return nil, err
+	}
+	config.Logger = logger
+	return client.NewClient(config)
+}
+
+// config loads the profile or environment and applies the flags that were set
+// explicitly
+func (f *connectionFlags) config() (*client.ClientConfig, error) {
+	config, err := f.baseConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool)
+	f.fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+
+	if set["url"] {
+		config.BaseURL = *f.baseURL
+	}
+	if set["timeout"] {
+		config.Timeout = *f.timeout
+	}
+	if set["retries"] {
+		config.MaxRetries = *f.maxRetries
+	}
+	if set["skip-health"] {
+		config.SkipValidation = *f.skipHealth
+	}
+
+	switch {
+	case set["api-key"]:
+		config.Authenticator = &client.APIKeyAuth{Header: *f.apiKeyHeader, Key: *f.apiKey}
+	case set["token"]:
+		config.Authenticator = client.NewBearerAuth(*f.token)
+	case set["user"]:
+		config.Authenticator = &client.BasicAuth{Username: *f.username, Password: *f.password}
+	}
+
+	if set["ca-cert"] || set["client-cert"] || set["client-key"] || set["insecure"] {
+		if config.TLS == nil {
+			config.TLS = &client.TLSConfig{}
+		}
+		if set["ca-cert"] {
+			config.TLS.CAFile = *f.caCert
+		}
+		if set["client-cert"] {
+			config.TLS.CertFile = *f.clientCert
+		}
+		if set["client-key"] {
+			config.TLS.KeyFile = *f.clientKey
+		}
+		if set["insecure"] {
+			config.TLS.InsecureSkipVerify = *f.insecure
+		}
+	}
+
+	return config, nil
+}
+
+// baseConfig loads the selected profile, or client.DefaultProfile, from the
+// profile file given by -config, NENDB_CONFIG or the user config directory.
+// Without a profile file it uses the environment alone.
+func (f *connectionFlags) baseConfig() (*client.ClientConfig, error) {
+	profile := *f.profile
+	if profile == "" {
+		profile = os.Getenv("NENDB_PROFILE")
+	}
+	path := *f.configFile
+	if path == "" {
+		path = client.DefaultProfilePath()
+	}
+
+	switch {
+	case path != "":
+		return client.LoadProfile(path, profile)
+	case profile != "":
+		return nil, fmt.Errorf("profile %q requested but no profile file found; use -config", profile)
+	default:
+		return client.ConfigFromEnv()
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/importer"
+)
+
+const importHelp = `Streams nodes and edges from CSV or JSON Lines files into NenDB. Nodes are
+imported first; edge endpoints refer to nodes by their -key column, which is
+only required when -edges is given or -key is set explicitly. Rows that fail
+are reported and skipped, and the command exits with status 1.
+
+Examples:
+  nendb import -nodes people.csv -label Person -edges knows.csv -type KNOWS
+  nendb import -nodes cities.jsonl -key code -label-column labels
+  nendb import -edges links.csv -numeric-ids -type LINKS`
+
+func setupImport(fs *flag.FlagSet) runFunc {
+	var (
+		nodesFile   = fs.String("nodes", "", "Node input file (.csv, .jsonl or .ndjson)")
+		edgesFile   = fs.String("edges", "", "Edge input file (.csv, .jsonl or .ndjson)")
+		format      = fs.String("format", "", "Input format (csv, jsonl); inferred from the file extension by default")
+		keyColumn   = fs.String("key", "id", "Node column holding the external key referenced by edges")
+		labels      = addListFlag(fs, "label", "Label added to every node; repeat or separate with commas")
+		labelColumn = fs.String("label-column", "", "Node column holding per-row labels")
+		nodeProps   = fs.String("node-props", "", "Comma-separated node property columns (default: all)")
+		sourceCol   = fs.String("source", "source", "Edge column holding the source node key")
+		targetCol   = fs.String("target", "target", "Edge column holding the target node key")
+		edgeType    = fs.String("type", "", "Edge type for every edge")
+		typeColumn  = fs.String("type-column", "", "Edge column holding per-row edge types")
+		edgeProps   = fs.String("edge-props", "", "Comma-separated edge property columns (default: all)")
+		numericIDs  = fs.Bool("numeric-ids", false, "Treat unknown edge endpoints as existing NenDB node IDs")
+		batchSize   = fs.Int("batch-size", 0, "Rows per request (default: client batch size)")
+		concurrency = fs.Int("concurrency", importer.DefaultConcurrency, "Maximum batches in flight")
+		inferTypes  = fs.Bool("infer-types", true, "Convert CSV cells that look like numbers or booleans")
+		quiet       = fs.Bool("quiet", false, "Do not report progress")
+	)
+	fs.Var((*listFlag)(labels), "labels", "Same as -label")
+	return func(e *env, args []string) error {
+		if *nodesFile == "" && *edgesFile == "" {
+			return usagef("at least one of -nodes or -edges is required")
+		}
+		if *edgesFile != "" && *edgeType == "" && *typeColumn == "" {
+			return usagef("-type or -type-column is required when importing edges")
+		}
+
+		// Keys only matter to edges imported in the same run
+		key := *keyColumn
+		keySet := false
+		fs.Visit(func(fl *flag.Flag) { keySet = keySet || fl.Name == "key" })
+		if *edgesFile == "" && !keySet {
+			key = ""
+		}
+
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		opts := importer.Options{
+			BatchSize:   *batchSize,
+			Concurrency: *concurrency,
+			InferTypes:  *inferTypes,
+		}
+		if !*quiet {
+			opts.Progress = func(p importer.Progress) {
+				fmt.Fprintf(e.errOut, "\r%s: %d read, %d created, %d failed", p.Kind, p.Read, p.Created, p.Failed)
+			}
+		}
+		im := importer.New(c, opts)
+
+		failed := 0
+		if *nodesFile != "" {
+			n, err := importFile(e, *nodesFile, *format, func(f *os.File, format importer.Format) (*importer.Stats, error) {
+				return im.ImportNodes(e.ctx, f, format, importer.NodeMapping{
+					KeyColumn:   key,
+					Labels:      *labels,
+					LabelColumn: *labelColumn,
+					Properties:  splitList(*nodeProps),
+				})
+			})
+			failed += n
+			if err != nil {
+				return err
+			}
+		}
+
+		if *edgesFile != "" {
+			n, err := importFile(e, *edgesFile, *format, func(f *os.File, format importer.Format) (*importer.Stats, error) {
+				return im.ImportEdges(e.ctx, f, format, importer.EdgeMapping{
+					SourceColumn:      *sourceCol,
+					TargetColumn:      *targetCol,
+					Type:              *edgeType,
+					TypeColumn:        *typeColumn,
+					Properties:        splitList(*edgeProps),
+					ResolveNumericIDs: *numericIDs,
+				})
+			})
+			failed += n
+			if err != nil {
+				return err
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d rows could not be imported", failed)
+		}
+		return nil
+	}
+}
+
+// importFile opens path, runs fn on it and prints a summary of the result. It
+// returns the number of rows that failed.
+func importFile(e *env, path, format string, fn func(*os.File, importer.Format) (*importer.Stats, error)) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	fileFormat := importer.Format(format)
+	if fileFormat == "" {
+		if fileFormat, err = importer.FormatFromPath(path); err != nil {
+			return 0, err
+		}
+	}
+
+	stats, err := fn(f, fileFormat)
+	fmt.Fprintln(e.errOut)
+	failed := 0
+	if stats != nil {
+		failed = stats.Failed
+		fmt.Fprintf(e.errOut, "%s: %d read, %d created, %d failed in %v\n", path, stats.Read, stats.Created, stats.Failed, stats.Duration.Round(time.Millisecond))
+		for _, rowErr := range stats.Errors {
+			fmt.Fprintf(e.errOut, "  %s: %v\n", path, rowErr)
+		}
+	}
+	if err != nil {
+		return failed, fmt.Errorf("import of %s failed: %w", path, err)
+	}
+	return failed, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// errInterrupted is returned by readLine when the user presses Ctrl-C
+var errInterrupted = errors.New("interrupted")
+
+// completer returns the candidates for the word ending at pos in line and
+// the position where that word starts
+type completer func(line string, pos int) (candidates []string, start int)
+
+// lineReader reads lines of input for the shell
+type lineReader interface {
+	// readLine shows prompt and returns the next line, io.EOF at end of
+	// input or errInterrupted
+	readLine(prompt string) (string, error)
+	// addHistory records an entered line
+	addHistory(line string)
+	// history returns the recorded lines, oldest first
+	history() []string
+}
+
+// historyList is the history shared by both readers
+type historyList struct {
+	lines []string
+	limit int
+}
+
+func (h *historyList) addHistory(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if h.limit > 0 && len(h.lines) > h.limit {
+		h.lines = h.lines[len(h.lines)-h.limit:]
+	}
+}
+
+func (h *historyList) history() []string {
+	return h.lines
+}
+
+// plainReader reads lines without editing, for input that is not a terminal
+type plainReader struct {
+	historyList
+	in     *bufio.Reader
+	out    io.Writer
+	prompt bool
+}
+
+func newPlainReader(in io.Reader, out io.Writer, prompt bool) *plainReader {
+	return &plainReader{historyList: historyList{limit: historyLimit}, in: bufio.NewReader(in), out: out, prompt: prompt}
+}
+
+func (r *plainReader) readLine(prompt string) (string, error) {
+	if r.prompt {
+		fmt.Fprint(r.out, prompt)
+	}
+	line, err := r.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// termReader is a line editor for interactive terminals. It supports cursor
+// movement, history with the arrow keys, common Emacs-style keys and tab
+// completion.
+type termReader struct {
+	historyList
+	in       *bufio.Reader
+	out      io.Writer
+	fd       int
+	complete completer
+}
+
+func newTermReader(in *os.File, out io.Writer, complete completer) *termReader {
+	return &termReader{historyList: historyList{limit: historyLimit}, in: bufio.NewReader(in), out: out, fd: int(in.Fd()), complete: complete}
+}
+
+// Keys recognised by the editor
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+func (r *termReader) readLine(prompt string) (string, error) {
+	state, err := makeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer restoreTerminal(r.fd, state)
+
+	e := &editState{prompt: prompt, out: r.out, historyPos: len(r.lines)}
+	e.refresh()
+	lastTab := false
+	for {
+		c, _, err := r.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		wasTab := lastTab
+		lastTab = false
+
+		switch c {
+		case keyEnter, '\n':
+			fmt.Fprint(r.out, "\r\n")
+			return string(e.buf), nil
+		case keyCtrlC:
+			fmt.Fprint(r.out, "^C\r\n")
+			return "", errInterrupted
+		case keyCtrlD:
+			if len(e.buf) == 0 {
+				fmt.Fprint(r.out, "\r\n")
+				return "", io.EOF
+			}
+			e.deleteForward()
+		case keyCtrlA:
+			e.pos = 0
+		case keyCtrlE:
+			e.pos = len(e.buf)
+		case keyCtrlB:
+			e.left()
+		case keyCtrlF:
+			e.right()
+		case keyCtrlK:
+			e.buf = e.buf[:e.pos]
+		case keyCtrlU:
+			e.buf = e.buf[e.pos:]
+			e.pos = 0
+		case keyCtrlW:
+			e.deleteWord()
+		case keyCtrlL:
+			fmt.Fprint(r.out, "\x1b[H\x1b[2J")
+		case keyCtrlP:
+			e.historyMove(r.lines, -1)
+		case keyCtrlN:
+			e.historyMove(r.lines, 1)
+		case keyBackspace, '\b':
+			e.backspace()
+		case keyTab:
+			r.completeWord(e, wasTab)
+			lastTab = true
+		case keyEscape:
+			r.escape(e)
+		default:
+			if unicode.IsPrint(c) {
+				e.insert(c)
+			}
+		}
+		e.refresh()
+	}
+}
+
+// escape handles the arrow, Home, End and Delete key sequences
+func (r *termReader) escape(e *editState) {
+	b, err := r.in.ReadByte()
+	if err != nil || (b != '[' && b != 'O') {
+		return
+	}
+	seq := ""
+	for {
+		c, err := r.in.ReadByte()
+		if err != nil {
+			return
+		}
+		seq += string(c)
+		if c >= 0x40 && c <= 0x7e {
+			break
+		}
+	}
+
+	switch seq {
+	case "A":
+		e.historyMove(r.lines, -1)
+	case "B":
+		e.historyMove(r.lines, 1)
+	case "C":
+		e.right()
+	case "D":
+		e.left()
+	case "H", "1~", "7~":
+		e.pos = 0
+	case "F", "4~", "8~":
+		e.pos = len(e.buf)
+	case "3~":
+		e.deleteForward()
+	}
+}
+
+// completeWord replaces the word before the cursor with the longest prefix
+// shared by the candidates, listing them when pressed twice
+func (r *termReader) completeWord(e *editState, list bool) {
+	if r.complete == nil {
+		return
+	}
+	line := string(e.buf)
+	bytePos := len(string(e.buf[:e.pos]))
+	candidates, start := r.complete(line, bytePos)
+	if len(candidates) == 0 {
+		return
+	}
+
+	prefix := commonPrefix(candidates)
+	word := line[start:bytePos]
+	if len(candidates) == 1 {
+		prefix += " "
+	}
+	if prefix != word && strings.HasPrefix(prefix, word) {
+		e.replace(utf8.RuneCountInString(line[:start]), prefix)
+		return
+	}
+	if list {
+		fmt.Fprint(r.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+	}
+}
+
+// editState is the line being edited
+type editState struct {
+	prompt     string
+	out        io.Writer
+	buf        []rune
+	pos        int
+	historyPos int
+	saved      []rune
+}
+
+func (e *editState) insert(c rune) {
+	e.buf = append(e.buf[:e.pos], append([]rune{c}, e.buf[e.pos:]...)...)
+	e.pos++
+}
+
+func (e *editState) replace(start int, text string) {
+	rest := append([]rune(text), e.buf[e.pos:]...)
+	e.buf = append(e.buf[:start], rest...)
+	e.pos = start + utf8.RuneCountInString(text)
+}
+
+func (e *editState) left() {
+	if e.pos > 0 {
+		e.pos--
+	}
+}
+
+func (e *editState) right() {
+	if e.pos < len(e.buf) {
+		e.pos++
+	}
+}
+
+func (e *editState) backspace() {
+	if e.pos > 0 {
+		e.buf = append(e.buf[:e.pos-1], e.buf[e.pos:]...)
+		e.pos--
+	}
+}
+
+func (e *editState) deleteForward() {
+	if e.pos < len(e.buf) {
+		e.buf = append(e.buf[:e.pos], e.buf[e.pos+1:]...)
+	}
+}
+
+func (e *editState) deleteWord() {
+	start := e.pos
+	for start > 0 && e.buf[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && e.buf[start-1] != ' ' {
+		start--
+	}
+	e.buf = append(e.buf[:start], e.buf[e.pos:]...)
+	e.pos = start
+}
+
+// historyMove steps through history, keeping the line being typed so it can
+// be returned to
+func (e *editState) historyMove(lines []string, delta int) {
+	next := e.historyPos + delta
+	if next < 0 || next > len(lines) {
+		return
+	}
+	if e.historyPos == len(lines) {
+		e.saved = append([]rune{}, e.buf...)
+	}
+	e.historyPos = next
+	if next == len(lines) {
+		e.buf = e.saved
+	} else {
+		e.buf = []rune(lines[next])
+	}
+	e.pos = len(e.buf)
+}
+
+// refresh redraws the prompt and line and places the cursor
+func (e *editState) refresh() {
+	var b strings.Builder
+	b.WriteString("\r")
+	b.WriteString(e.prompt)
+	b.WriteString(string(e.buf))
+	b.WriteString("\x1b[K")
+	if back := len(e.buf) - e.pos; back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+	io.WriteString(e.out, b.String())
+}
+
+// commonPrefix returns the longest prefix shared by all of words
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
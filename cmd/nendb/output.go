@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// outputFormat selects how results are printed
+type outputFormat string
+
+const (
+	formatTable  outputFormat = "table"
+	formatJSON   outputFormat = "json"
+	formatYAML   outputFormat = "yaml"
+	formatCSV    outputFormat = "csv"
+	formatNDJSON outputFormat = "ndjson"
+)
+
+// outputFormats lists the supported formats in the order shown in help text
+var outputFormats = []outputFormat{formatTable, formatJSON, formatYAML, formatCSV, formatNDJSON}
+
+// String and Set let an outputFormat be used as a flag value
+func (f *outputFormat) String() string { return string(*f) }
+
+func (f *outputFormat) Set(name string) error {
+	format, err := parseOutputFormat(name)
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// formatNames returns the supported format names separated by sep
+func formatNames(sep string) string {
+	names := make([]string, len(outputFormats))
+	for i, f := range outputFormats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, sep)
+}
+
+// parseOutputFormat validates a format name
+func parseOutputFormat(name string) (outputFormat, error) {
+	for _, f := range outputFormats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q (want %s)", name, formatNames(", "))
+}
+
+// render writes v to w in the given format
+func render(w io.Writer, format outputFormat, v interface{}) error {
+	switch format {
+	case formatJSON:
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output: %v", err)
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	case formatYAML:
+		return writeYAML(w, v)
+	case formatNDJSON:
+		return writeNDJSON(w, v)
+	case formatCSV:
+		t, err := tabulate(v)
+		if err != nil {
+			return err
+		}
+		return t.writeCSV(w)
+	default:
+		t, err := tabulate(v)
+		if err != nil {
+			return err
+		}
+		return t.write(w)
+	}
+}
+
+// table is a result laid out as named columns
+type table struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+// tabulate lays out the results printed by the CLI as a table
+func tabulate(v interface{}) (*table, error) {
+	switch val := v.(type) {
+	case *types.GraphNode:
+		return tabulate([]types.GraphNode{*val})
+	case []types.GraphNode:
+		t := &table{columns: []string{"id", "labels", "properties"}}
+		for _, n := range val {
+			t.rows = append(t.rows, []interface{}{n.ID, strings.Join(n.Labels, ","), n.Properties})
+		}
+		return t, nil
+	case *types.GraphEdge:
+		return tabulate([]types.GraphEdge{*val})
+	case []types.GraphEdge:
+		t := &table{columns: []string{"id", "source", "target", "type", "properties"}}
+		for _, e := range val {
+			t.rows = append(t.rows, []interface{}{e.ID, e.Source, e.Target, e.Type, e.Properties})
+		}
+		return t, nil
+	case *client.Result:
+		t := &table{columns: val.Columns}
+		for _, row := range val.Rows {
+			cells := make([]interface{}, len(row))
+			for i, raw := range row {
+				cells[i] = raw
+			}
+			t.rows = append(t.rows, cells)
+		}
+		return t, nil
+	case *types.BFSResult:
+		return &table{
+			columns: []string{"status", "depth", "visited_nodes", "path"},
+			rows:    [][]interface{}{{val.Status, val.Depth, val.VisitedNodes, val.Path}},
+		}, nil
+	case *types.DijkstraResult:
+		return &table{
+			columns: []string{"status", "total_cost", "shortest_path"},
+			rows:    [][]interface{}{{val.Status, val.TotalCost, val.ShortestPath}},
+		}, nil
+	case *types.PageRankResult:
+		t := &table{columns: []string{"node", "score"}}
+		ids := make([]int, 0, len(val.NodeScores))
+		for id := range val.NodeScores {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			if val.NodeScores[ids[i]] != val.NodeScores[ids[j]] {
+				return val.NodeScores[ids[i]] > val.NodeScores[ids[j]]
+			}
+			return ids[i] < ids[j]
+		})
+		for _, id := range ids {
+			t.rows = append(t.rows, []interface{}{id, val.NodeScores[id]})
+		}
+		return t, nil
+	case map[string]interface{}:
+		t := &table{columns: []string{"key", "value"}}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			t.rows = append(t.rows, []interface{}{k, val[k]})
+		}
+		return t, nil
+	}
+
+	// Anything else is shown as the fields of its JSON object
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return &table{columns: []string{"value"}, rows: [][]interface{}{{json.RawMessage(raw)}}}, nil
+	}
+	return tabulate(m)
+}
+
+// write prints the table with aligned columns
+func (t *table) write(w io.Writer) error {
+	cells := make([][]string, len(t.rows))
+	widths := make([]int, len(t.columns))
+	for i, c := range t.columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for r, row := range t.rows {
+		cells[r] = make([]string, len(t.columns))
+		for i := range t.columns {
+			if i < len(row) {
+				cells[r][i] = formatCell(row[i])
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cells[r][i]))
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(values []string) {
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(v)
+			if i < len(values)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	header := make([]string, len(t.columns))
+	rule := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		header[i] = strings.ToUpper(c)
+		rule[i] = strings.Repeat("-", widths[i])
+	}
+	writeRow(header)
+	writeRow(rule)
+	for _, row := range cells {
+		writeRow(row)
+	}
+	fmt.Fprintf(&b, "(%d %s)\n", len(cells), plural(len(cells), "row", "rows"))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCSV prints the table as CSV with a header row
+func (t *table) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write(t.columns)
+	for _, row := range t.rows {
+		record := make([]string, len(t.columns))
+		for i := range record {
+			if i < len(row) {
+				record[i] = formatCell(row[i])
+			}
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeNDJSON prints one JSON object per line: one per node, edge, query row
+// or PageRank score, and a single line for other results
+func writeNDJSON(w io.Writer, v interface{}) error {
+	var records []interface{}
+	switch val := v.(type) {
+	case []types.GraphNode:
+		for i := range val {
+			records = append(records, &val[i])
+		}
+	case []types.GraphEdge:
+		for i := range val {
+			records = append(records, &val[i])
+		}
+	case *client.Result:
+		for _, row := range val.Rows {
+			record := make(map[string]json.RawMessage, len(val.Columns))
+			for i, column := range val.Columns {
+				if i < len(row) {
+					record[column] = row[i]
+				}
+			}
+			records = append(records, record)
+		}
+	case *types.PageRankResult:
+		t, err := tabulate(val)
+		if err != nil {
+			return err
+		}
+		for _, row := range t.rows {
+			records = append(records, map[string]interface{}{"node": row[0], "score": row[1]})
+		}
+	default:
+		records = []interface{}{v}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to marshal output: %v", err)
+		}
+	}
+	return nil
+}
+
+// formatCell renders a single value on one line
+func formatCell(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.RawMessage:
+		var s string
+		if json.Unmarshal(val, &s) == nil {
+			return s
+		}
+		return string(val)
+	case fmt.Stringer:
+		return val.String()
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+)
+
+// historyLimit is the number of entries kept in the history file
+const historyLimit = 1000
+
+// queryKeywords are offered by tab completion outside of patterns
+var queryKeywords = []string{
+	"AND", "AS", "BY", "CONTAINS", "COUNT", "CREATE", "DELETE", "DETACH",
+	"DISTINCT", "ENDS", "IN", "IS", "LIMIT", "MATCH", "MERGE", "NOT", "NULL",
+	"OPTIONAL", "OR", "ORDER", "REMOVE", "RETURN", "SET", "SKIP", "STARTS",
+	"WHERE", "WITH",
+}
+
+// metaCommand is a shell command starting with a colon
+type metaCommand struct {
+	usage string
+	help  string
+	run   func(s *shell, ctx context.Context, args []string) error
+}
+
+var metaCommands map[string]metaCommand
+
+func init() {
+	// Assigned in init because :help refers to the table itself
+	metaCommands = map[string]metaCommand{
+		"help":     {":help", "Show this help", (*shell).metaHelp},
+		"node":     {":node <id>", "Get a node by ID", (*shell).metaNode},
+		"edge":     {":edge <id>", "Get an edge by ID", (*shell).metaEdge},
+		"bfs":      {":bfs <start> <target> [depth]", "Run breadth-first search", (*shell).metaBFS},
+		"dijkstra": {":dijkstra <start> <target>", "Find the shortest path", (*shell).metaDijkstra},
+		"pagerank": {":pagerank [iterations] [tolerance]", "Run PageRank", (*shell).metaPageRank},
+		"stats":    {":stats", "Show database statistics", (*shell).metaStats},
+		"format":   {":format [" + formatNames("|") + "]", "Show or set the output format", (*shell).metaFormat},
+		"refresh":  {":refresh", "Reload labels, edge types and property keys for completion", (*shell).metaRefresh},
+		"history":  {":history", "Show command history", (*shell).metaHistory},
+		"quit":     {":quit", "Leave the shell (also :exit or Ctrl-D)", nil},
+	}
+}
+
+// errQuit is returned by meta commands that end the session
+var errQuit = errors.New("quit")
+
+const shellHelp = `Starts an interactive shell. Queries may span several lines and run when a
+line ends with ";" or an empty line is entered. Lines starting with ":" are
+shell commands; type :help to list them. Tab completes keywords, labels
+after ":", edge types inside [...] and property keys after ".". Results are
+shown as tables unless -o selects another format. Piped input runs as a
+script whose exit code reflects the last error.
+
+Examples:
+  nendb shell
+  nendb shell -profile prod -o json
+  echo ':stats' | nendb shell`
+
+func setupShell(fs *flag.FlagSet) runFunc {
+	historyFile := fs.String("history", defaultHistoryPath(), "History file; empty disables saving history")
+	return func(e *env, args []string) error {
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		s := &shell{client: c, out: e.out, errOut: e.errOut, format: e.outputFormat(formatTable)}
+		if err := s.refreshCompletions(e.ctx); err != nil {
+			fmt.Fprintf(e.errOut, "Warning: completion unavailable: %v\n", err)
+		}
+
+		interactive := isTerminal(int(os.Stdin.Fd()))
+		s.script = !interactive
+		var reader lineReader
+		if interactive {
+			reader = newTermReader(os.Stdin, e.out, s.complete)
+			fmt.Fprintf(e.out, "NenDB shell %s. Type :help for commands, :quit to leave.\n", version)
+		} else {
+			reader = newPlainReader(os.Stdin, e.out, false)
+		}
+		if *historyFile != "" {
+			loadHistory(*historyFile, reader)
+		}
+
+		err = s.run(e.ctx, reader)
+		if *historyFile != "" && interactive {
+			if saveErr := saveHistory(*historyFile, reader.history()); saveErr != nil {
+				fmt.Fprintf(e.errOut, "Warning: failed to save history: %v\n", saveErr)
+			}
+		}
+		return err
+	}
+}
+
+// shell runs queries and meta commands read from a lineReader
+type shell struct {
+	client *client.NenDBClient
+	out    io.Writer
+	errOut io.Writer
+	format outputFormat
+	reader lineReader
+	// script makes run return the last error once input ends, so that piped
+	// input sets the exit code
+	script bool
+
+	labels       []string
+	edgeTypes    []string
+	propertyKeys []string
+}
+
+// run reads and executes input until end of input or :quit. Errors are
+// reported as they happen; in script mode the last one is also returned.
+func (s *shell) run(ctx context.Context, r lineReader) error {
+	s.reader = r
+	var pending []string
+	var lastErr error
+	track := func(err error) {
+		if err != nil {
+			lastErr = err
+		}
+	}
+	done := func() error {
+		if !s.script || lastErr == nil {
+			return nil
+		}
+		return &reportedError{err: lastErr}
+	}
+	for {
+		prompt := "nendb> "
+		if len(pending) > 0 {
+			prompt = "  ...> "
+		}
+		line, err := r.readLine(prompt)
+		switch {
+		case errors.Is(err, errInterrupted):
+			pending = nil
+			continue
+		case err == io.EOF:
+			if len(pending) > 0 {
+				track(s.runQuery(ctx, r, pending))
+			}
+			return done()
+		case err != nil:
+			return err
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if len(pending) == 0 {
+			if trimmed == "" {
+				continue
+			}
+			if strings.HasPrefix(trimmed, ":") {
+				r.addHistory(trimmed)
+				err := s.runMeta(ctx, trimmed)
+				if errors.Is(err, errQuit) {
+					return done()
+				}
+				track(err)
+				continue
+			}
+		}
+
+		if trimmed != "" {
+			pending = append(pending, line)
+		}
+		if trimmed == "" || strings.HasSuffix(trimmed, ";") {
+			track(s.runQuery(ctx, r, pending))
+			pending = nil
+		}
+	}
+}
+
+// runQuery executes a query made of one or more lines
+func (s *shell) runQuery(ctx context.Context, r lineReader, lines []string) error {
+	parts := make([]string, len(lines))
+	for i, l := range lines {
+		parts[i] = strings.TrimSpace(l)
+	}
+	r.addHistory(strings.Join(parts, " "))
+
+	query := strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";")
+	return s.exec(ctx, func(ctx context.Context) error {
+		result, err := s.client.Query(ctx, query, nil)
+		if err != nil {
+			return err
+		}
+		return render(s.out, s.format, result)
+	})
+}
+
+// runMeta executes a line starting with a colon
+func (s *shell) runMeta(ctx context.Context, line string) error {
+	fields := strings.Fields(strings.TrimPrefix(line, ":"))
+	if len(fields) == 0 {
+		return s.report(fmt.Errorf("missing command after ':'; type :help"))
+	}
+	name := fields[0]
+	if name == "exit" || name == "q" {
+		name = "quit"
+	}
+	cmd, ok := metaCommands[name]
+	if !ok {
+		return s.report(fmt.Errorf("unknown command :%s; type :help", fields[0]))
+	}
+	if cmd.run == nil {
+		return errQuit
+	}
+	return s.exec(ctx, func(ctx context.Context) error {
+		return cmd.run(s, ctx, fields[1:])
+	})
+}
+
+// exec runs fn with a context cancelled by Ctrl-C and reports its error
+func (s *shell) exec(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	return s.report(fn(ctx))
+}
+
+func (s *shell) report(err error) error {
+	if err != nil {
+		fmt.Fprintf(s.errOut, "Error: %v\n", err)
+	}
+	return err
+}
+
+func (s *shell) metaHelp(ctx context.Context, args []string) error {
+	names := make([]string, 0, len(metaCommands))
+	for name := range metaCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(s.out, "Queries run when a line ends with ';' or after an empty line.")
+	fmt.Fprintln(s.out, "Commands:")
+	for _, name := range names {
+		cmd := metaCommands[name]
+		fmt.Fprintf(s.out, "  %-36s %s\n", cmd.usage, cmd.help)
+	}
+	return nil
+}
+
+func (s *shell) metaNode(ctx context.Context, args []string) error {
+	ids, err := intArgs(args, 1, 1, "node ID")
+	if err != nil {
+		return err
+	}
+	node, err := s.client.GetNode(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return render(s.out, s.format, node)
+}
+
+func (s *shell) metaEdge(ctx context.Context, args []string) error {
+	ids, err := intArgs(args, 1, 1, "edge ID")
+	if err != nil {
+		return err
+	}
+	edge, err := s.client.GetEdge(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	return render(s.out, s.format, edge)
+}
+
+func (s *shell) metaBFS(ctx context.Context, args []string) error {
+	values, err := intArgs(args, 2, 3, "start node ID, target node ID and optional depth")
+	if err != nil {
+		return err
+	}
+	depth := 10
+	if len(values) == 3 {
+		depth = values[2]
+	}
+	result, err := s.client.RunBFS(ctx, values[0], values[1], depth)
+	if err != nil {
+		return err
+	}
+	return render(s.out, s.format, result)
+}
+
+func (s *shell) metaDijkstra(ctx context.Context, args []string) error {
+	values, err := intArgs(args, 2, 2, "start and target node IDs")
+	if err != nil {
+		return err
+	}
+	result, err := s.client.RunDijkstra(ctx, values[0], values[1])
+	if err != nil {
+		return err
+	}
+	return render(s.out, s.format, result)
+}
+
+func (s *shell) metaPageRank(ctx context.Context, args []string) error {
+	if len(args) > 2 {
+		return fmt.Errorf("usage: %s", metaCommands["pagerank"].usage)
+	}
+	iterations, tolerance := 100, 0.001
+	var err error
+	if len(args) > 0 {
+		if iterations, err = strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("invalid iterations: %s", args[0])
+		}
+	}
+	if len(args) > 1 {
+		if tolerance, err = strconv.ParseFloat(args[1], 64); err != nil {
+			return fmt.Errorf("invalid tolerance: %s", args[1])
+		}
+	}
+	result, err := s.client.RunPageRank(ctx, iterations, tolerance)
+	if err != nil {
+		return err
+	}
+	return render(s.out, s.format, result)
+}
+
+func (s *shell) metaStats(ctx context.Context, args []string) error {
+	stats, err := s.client.GetStatistics(ctx)
+	if err != nil {
+		return err
+	}
+	s.setCompletions(stats)
+	return render(s.out, s.format, stats)
+}
+
+func (s *shell) metaFormat(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintf(s.out, "Output format: %s\n", s.format)
+		return nil
+	}
+	format, err := parseOutputFormat(args[0])
+	if err != nil {
+		return err
+	}
+	s.format = format
+	return nil
+}
+
+func (s *shell) metaRefresh(ctx context.Context, args []string) error {
+	if err := s.refreshCompletions(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Loaded %d labels, %d edge types and %d property keys\n", len(s.labels), len(s.edgeTypes), len(s.propertyKeys))
+	return nil
+}
+
+func (s *shell) metaHistory(ctx context.Context, args []string) error {
+	for i, line := range s.reader.history() {
+		fmt.Fprintf(s.out, "%4d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+// intArgs parses between min and max integer arguments
+func intArgs(args []string, min, max int, what string) ([]int, error) {
+	if len(args) < min || len(args) > max {
+		return nil, fmt.Errorf("expected %s", what)
+	}
+	values := make([]int, len(args))
+	for i, a := range args {
+		v, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s", a)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// refreshCompletions loads completion candidates from the server statistics
+func (s *shell) refreshCompletions(ctx context.Context) error {
+	stats, err := s.client.GetStatistics(ctx)
+	if err != nil {
+		return err
+	}
+	s.setCompletions(stats)
+	return nil
+}
+
+func (s *shell) setCompletions(stats map[string]interface{}) {
+	s.labels = statisticNames(stats["labels"])
+	s.edgeTypes = statisticNames(stats["edge_types"])
+	s.propertyKeys = statisticNames(stats["property_keys"])
+}
+
+// statisticNames reads names from a statistics entry, which may be a list of
+// names or an object keyed by name
+func statisticNames(v interface{}) []string {
+	var names []string
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	case map[string]interface{}:
+		for name := range val {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// complete returns completion candidates for the word before pos: meta
+// commands after a leading ":", edge types after ":" inside [...], labels
+// after ":" elsewhere, property keys after "." and keywords otherwise
+func (s *shell) complete(line string, pos int) ([]string, int) {
+	start := pos
+	for start > 0 && isWordByte(line[start-1]) {
+		start--
+	}
+	word := line[start:pos]
+	before := line[:start]
+
+	var pool []string
+	switch {
+	case strings.TrimSpace(before) == ":":
+		for name := range metaCommands {
+			pool = append(pool, name)
+		}
+		pool = append(pool, "exit")
+	case strings.HasSuffix(before, ":"):
+		if strings.LastIndex(before, "[") > strings.LastIndex(before, "]") {
+			pool = s.edgeTypes
+		} else {
+			pool = s.labels
+		}
+	case strings.HasSuffix(before, "."):
+		pool = s.propertyKeys
+	case strings.HasPrefix(strings.TrimSpace(before), ":"):
+		return nil, start
+	default:
+		pool = queryKeywords
+	}
+
+	var matches []string
+	for _, candidate := range pool {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(word)) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches, start
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// defaultHistoryPath returns ~/.nendb_history, or an empty string if the
+// home directory is unknown
+func defaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".nendb_history")
+}
+
+// loadHistory adds the lines of a history file to r, ignoring a missing file
+func loadHistory(path string, r lineReader) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		r.addHistory(scanner.Text())
+	}
+}
+
+// saveHistory writes the most recent history lines to path
+func saveHistory(path string, lines []string) error {
+	if len(lines) > historyLimit {
+		lines = lines[len(lines)-historyLimit:]
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o600)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func newTestShell(t *testing.T) (*shell, *nendbtest.Server, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	srv := nendbtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	config := client.DefaultConfig()
+	config.BaseURL = srv.URL
+	config.MaxRetries = 0
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	return &shell{client: c, out: &out, errOut: &errOut, format: formatTable}, srv, &out, &errOut
+}
+
+func TestShellRunsQueriesAndMetaCommands(t *testing.T) {
+	s, srv, out, errOut := newTestShell(t)
+	srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice"})
+	srv.AddNode([]string{"City"}, map[string]interface{}{"name": "Lisbon"})
+
+	input := strings.Join([]string{
+		":node 1",
+		"MATCH (n:Person)",
+		"RETURN n;",
+		":format json",
+		":edge 99",
+		":bogus",
+		":history",
+		":quit",
+		":stats",
+	}, "\n")
+	r := newPlainReader(strings.NewReader(input), out, false)
+	if err := s.run(context.Background(), r); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "ID  LABELS  PROPERTIES") || !strings.Contains(got, `{"name":"Alice"}`) {
+		t.Errorf("Expected node table, got:\n%s", got)
+	}
+	if !strings.Contains(got, "(1 row)") {
+		t.Errorf("Expected the multi-line query to return one row, got:\n%s", got)
+	}
+	if !strings.Contains(errOut.String(), "not found") || !strings.Contains(errOut.String(), "unknown command :bogus") {
+		t.Errorf("Expected errors to be reported, got:\n%s", errOut.String())
+	}
+	if !strings.Contains(got, "MATCH (n:Person) RETURN n;") {
+		t.Errorf("Expected the query to be recorded as one history entry, got:\n%s", got)
+	}
+	if strings.Contains(got, "node_count") {
+		t.Error("Expected input after :quit to be ignored")
+	}
+	if s.format != formatJSON {
+		t.Errorf("Expected :format to switch to JSON, got %s", s.format)
+	}
+}
+
+func TestShellCompletion(t *testing.T) {
+	s, srv, _, _ := newTestShell(t)
+	a := srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice", "nickname": "Al"})
+	b := srv.AddNode([]string{"Place"}, nil)
+	if _, err := srv.AddEdge(a.ID, b.ID, "VISITED", map[string]interface{}{"year": 2020}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.refreshCompletions(context.Background()); err != nil {
+		t.Fatalf("refreshCompletions failed: %v", err)
+	}
+
+	tests := []struct {
+		line  string
+		want  []string
+		start int
+	}{
+		{"MATCH (n:P", []string{"Person", "Place"}, 9},
+		{"MATCH ()-[r:V", []string{"VISITED"}, 12},
+		{"MATCH (n) WHERE n.n", []string{"name", "nickname"}, 18},
+		{"match (n) ret", []string{"RETURN"}, 10},
+		{":no", []string{"node"}, 1},
+		{":node 1", nil, 6},
+	}
+	for _, tt := range tests {
+		got, start := s.complete(tt.line, len(tt.line))
+		if !reflect.DeepEqual(got, tt.want) || start != tt.start {
+			t.Errorf("complete(%q) = %v, %d; want %v, %d", tt.line, got, start, tt.want, tt.start)
+		}
+	}
+}
+
+func TestLineEditing(t *testing.T) {
+	var out bytes.Buffer
+	e := &editState{out: &out}
+	for _, c := range "MATCH (n)" {
+		e.insert(c)
+	}
+	e.deleteWord()
+	if string(e.buf) != "MATCH " {
+		t.Errorf("Expected deleteWord to remove the last word, got %q", string(e.buf))
+	}
+
+	history := []string{"first", "second"}
+	e.historyPos = len(history)
+	e.historyMove(history, -1)
+	e.historyMove(history, -1)
+	if string(e.buf) != "first" {
+		t.Errorf("Expected to reach the oldest history entry, got %q", string(e.buf))
+	}
+	e.historyMove(history, 1)
+	e.historyMove(history, 1)
+	if string(e.buf) != "MATCH " {
+		t.Errorf("Expected to return to the line being typed, got %q", string(e.buf))
+	}
+
+	if p := commonPrefix([]string{"Person", "Place", "Pet"}); p != "P" {
+		t.Errorf("commonPrefix = %q, want P", p)
+	}
+}
+
+func TestShellScriptErrors(t *testing.T) {
+	s, srv, out, errOut := newTestShell(t)
+	srv.AddNode([]string{"Person"}, nil)
+	s.script = true
+
+	r := newPlainReader(strings.NewReader(":node 999\n:node 1\n"), out, false)
+	err := s.run(context.Background(), r)
+	if exitCode(err) != exitNotFound {
+		t.Errorf("Expected the last error to set the exit code, got %v", err)
+	}
+	if strings.Count(errOut.String(), "Error:") != 1 {
+		t.Errorf("Expected the error to be reported once, got:\n%s", errOut.String())
+	}
+
+	r = newPlainReader(strings.NewReader(":bogus\n:quit\n"), out, false)
+	if err := s.run(context.Background(), r); exitCode(err) != exitError {
+		t.Errorf("Expected :quit to keep the last error, got %v", err)
+	}
+
+	s.script = false
+	r = newPlainReader(strings.NewReader(":node 999\n"), out, false)
+	if err := s.run(context.Background(), r); err != nil {
+		t.Errorf("Expected interactive sessions to end without error, got %v", err)
+	}
+}
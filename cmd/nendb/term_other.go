@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import "errors"
+
+// terminalState is unused on platforms without termios support
+type terminalState struct{}
+
+// isTerminal always reports false, so the shell reads plain lines
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (*terminalState, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func restoreTerminal(fd int, state *terminalState) error {
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// terminalState is the saved terminal mode restored by restoreTerminal
+type terminalState struct {
+	termios syscall.Termios
+}
+
+// isTerminal reports whether fd refers to a terminal
+func isTerminal(fd int) bool {
+	var t syscall.Termios
+	return ioctlTermios(fd, ioctlGetTermios, &t) == nil
+}
+
+// makeRaw puts the terminal into raw mode, returning the previous state
+func makeRaw(fd int) (*terminalState, error) {
+	var old syscall.Termios
+	if err := ioctlTermios(fd, ioctlGetTermios, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctlTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return &terminalState{termios: old}, nil
+}
+
+// restoreTerminal puts the terminal back into a saved mode
+func restoreTerminal(fd int, state *terminalState) error {
+	return ioctlTermios(fd, ioctlSetTermios, &state.termios)
+}
+
+func ioctlTermios(fd int, req uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
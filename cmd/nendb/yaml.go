@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// writeYAML prints v as a YAML document. The value is converted through its
+// JSON encoding first, so field names and typed property values match the
+// json output; object keys are sorted.
+func writeYAML(w io.Writer, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	var b strings.Builder
+	if isYAMLScalar(generic) {
+		b.WriteString(yamlScalar(generic))
+		b.WriteString("\n")
+	} else {
+		writeYAMLBlock(&b, generic, 0, false)
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// writeYAMLBlock writes a non-empty map or list. When inline is set the
+// first line continues a "- " already written by the enclosing list.
+func writeYAMLBlock(b *strings.Builder, v interface{}, indent int, inline bool) {
+	pad := strings.Repeat(" ", indent)
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 || !inline {
+				b.WriteString(pad)
+			}
+			b.WriteString(yamlString(k))
+			b.WriteString(":")
+			switch child := val[k].(type) {
+			case map[string]interface{}:
+				if isYAMLScalar(child) {
+					break
+				}
+				b.WriteString("\n")
+				writeYAMLBlock(b, child, indent+2, false)
+				continue
+			case []interface{}:
+				if isYAMLScalar(child) {
+					break
+				}
+				b.WriteString("\n")
+				writeYAMLBlock(b, child, indent, false)
+				continue
+			}
+			b.WriteString(" ")
+			b.WriteString(yamlScalar(val[k]))
+			b.WriteString("\n")
+		}
+	case []interface{}:
+		for i, item := range val {
+			if i > 0 || !inline {
+				b.WriteString(pad)
+			}
+			b.WriteString("- ")
+			if isYAMLScalar(item) {
+				b.WriteString(yamlScalar(item))
+				b.WriteString("\n")
+				continue
+			}
+			writeYAMLBlock(b, item, indent+2, true)
+		}
+	}
+}
+
+// isYAMLScalar reports whether v is written on a single line: scalars and
+// empty maps and lists
+func isYAMLScalar(v interface{}) bool {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return len(val) == 0
+	case []interface{}:
+		return len(val) == 0
+	}
+	return true
+}
+
+func yamlScalar(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(val)
+	case json.Number:
+		return val.String()
+	case string:
+		return yamlString(val)
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return yamlString(fmt.Sprint(v))
+}
+
+// yamlString quotes s when a YAML parser would otherwise read it as another
+// type or as syntax. JSON string escapes are valid in double-quoted YAML.
+func yamlString(s string) string {
+	if !yamlNeedsQuotes(s) {
+		return s
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	// Indicators, and characters that may start a number or date
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`+.0123456789~", rune(s[0])) {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null":
+		return true
+	}
+	return false
+}
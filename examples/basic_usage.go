@@ -161,7 +161,12 @@ func main() {
 	if err != nil {
 		log.Printf("Custom query failed: %v", err)
 	} else {
-		fmt.Printf("✓ Query result: %+v\n", result)
+		fmt.Printf("✓ Query returned %d rows in %v\n", result.Len(), result.Summary.RoundTrip)
+		if nodes, err := result.Nodes("n"); err == nil {
+			for _, n := range nodes {
+				fmt.Printf("  - node %d %v\n", n.ID, n.Labels)
+			}
+		}
 	}
 
 	// Get database statistics
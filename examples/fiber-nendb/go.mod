@@ -1,6 +1,6 @@
 module github.com/nen-co/nendb-go/examples/fiber-nendb
 
-go 1.21
+go 1.23
 
 require (
 	github.com/gofiber/fiber/v2 v2.52.0
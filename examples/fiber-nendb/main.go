@@ -61,12 +61,12 @@ func main() {
 			"version": "1.0.0",
 			"endpoints": fiber.Map{
 				"GET  /graph":           "Get entire graph structure",
-				"GET  /nodes":           "Get all nodes",
+				"GET  /nodes":           "List nodes (?label=&limit=&cursor=)",
 				"GET  /nodes/:id":       "Get node by ID",
 				"POST /nodes":           "Create new node",
 				"PUT  /nodes/:id":       "Update node",
 				"DELETE /nodes/:id":     "Delete node",
-				"GET  /edges":           "Get all edges",
+				"GET  /edges":           "List edges (?type=&limit=&cursor=)",
 				"GET  /edges/:id":       "Get edge by ID",
 				"POST /edges":           "Create new edge",
 				"PUT  /edges/:id":       "Update edge",
@@ -104,9 +104,24 @@ func main() {
 
 	// Node operations
 	app.Get("/nodes", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		page, err := nendbClient.ListNodes(ctx, &client.NodeListOptions{
+			Label:  c.Query("label"),
+			Limit:  c.QueryInt("limit", client.DefaultPageSize),
+			Cursor: c.Query("cursor"),
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to list nodes",
+			})
+		}
+
 		return c.JSON(fiber.Map{
-			"success": true,
-			"message": "Get all nodes endpoint - implement pagination for large graphs",
+			"success":     true,
+			"data":        page.Nodes,
+			"next_cursor": page.NextCursor,
 		})
 	})
 
@@ -253,9 +268,24 @@ func main() {
 
 	// Edge operations
 	app.Get("/edges", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		page, err := nendbClient.ListEdges(ctx, &client.EdgeListOptions{
+			Type:   c.Query("type"),
+			Limit:  c.QueryInt("limit", client.DefaultPageSize),
+			Cursor: c.Query("cursor"),
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to list edges",
+			})
+		}
+
 		return c.JSON(fiber.Map{
-			"success": true,
-			"message": "Get all edges endpoint - implement pagination for large graphs",
+			"success":     true,
+			"data":        page.Edges,
+			"next_cursor": page.NextCursor,
 		})
 	})
 
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultAPIKeyHeader is the header used by APIKeyAuth when none is given
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// tokenExpiryLeeway refreshes bearer tokens slightly before they expire, so a
+// token does not lapse while a request is in flight
+const tokenExpiryLeeway = 30 * time.Second
+
+// Authenticator adds credentials to every outgoing request. It is called once
+// per attempt, so retried requests pick up refreshed credentials.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate implements Authenticator
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// APIKeyAuth sends a static API key in a request header
+type APIKeyAuth struct {
+	// Header defaults to DefaultAPIKeyHeader
+	Header string
+	Key    string
+}
+
+// Authenticate implements Authenticator
+func (a *APIKeyAuth) Authenticate(req *http.Request) error {
+	header := a.Header
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	req.Header.Set(header, a.Key)
+	return nil
+}
+
+// BasicAuth sends HTTP basic credentials
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate implements Authenticator
+func (a *BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// TokenSource returns a bearer token and the time it expires. A zero expiry
+// means the token does not expire.
+type TokenSource func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// BearerAuth sends an "Authorization: Bearer" header. Tokens come from Refresh,
+// which is called on first use, shortly before the cached token expires, and
+// after the server rejects a request with 401 Unauthorized.
+type BearerAuth struct {
+	Refresh TokenSource
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewBearerAuth returns a BearerAuth that always sends token
+func NewBearerAuth(token string) *BearerAuth {
+	return NewRefreshingBearerAuth(func(ctx context.Context) (string, time.Time, error) {
+		return token, time.Time{}, nil
+	})
+}
+
+// NewRefreshingBearerAuth returns a BearerAuth that obtains tokens from refresh
+func NewRefreshingBearerAuth(refresh TokenSource) *BearerAuth {
+	return &BearerAuth{Refresh: refresh}
+}
+
+// Authenticate implements Authenticator
+func (a *BearerAuth) Authenticate(req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || (!a.expiry.IsZero() && time.Until(a.expiry) < tokenExpiryLeeway) {
+		token, expiry, err := a.Refresh(req.Context())
+		if err != nil {
+			return err
+		}
+		a.token, a.expiry = token, expiry
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// Invalidate drops the cached token so the next request refreshes it
+func (a *BearerAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = ""
+}
+
+// invalidator is implemented by authenticators whose credentials can be
+// refreshed after the server rejects them
+type invalidator interface {
+	Invalidate()
+}
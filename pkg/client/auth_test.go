@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// headerServer records the headers of the last request and answers 200 OK
+func headerServer(t *testing.T) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+	var last atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last.Store(r.Header.Clone())
+		fmt.Fprint(w, `{"status": "healthy"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &last
+}
+
+func TestStaticAuthenticators(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   Authenticator
+		header string
+		want   string
+	}{
+		{"api key default header", &APIKeyAuth{Key: "secret"}, "X-API-Key", "secret"},
+		{"api key custom header", &APIKeyAuth{Header: "X-NenDB-Key", Key: "secret"}, "X-NenDB-Key", "secret"},
+		{"basic", &BasicAuth{Username: "alice", Password: "pw"}, "Authorization", "Basic YWxpY2U6cHc="},
+		{"bearer", NewBearerAuth("tok"), "Authorization", "Bearer tok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, last := headerServer(t)
+			client, err := NewClient(&ClientConfig{BaseURL: srv.URL, Timeout: 5 * time.Second, Authenticator: tt.auth})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			if err := client.Health(); err != nil {
+				t.Fatalf("Health failed: %v", err)
+			}
+			if got := last.Load().(http.Header).Get(tt.header); got != tt.want {
+				t.Errorf("Expected %s %q, got %q", tt.header, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBearerAuthRefreshesExpiredAndRejectedTokens(t *testing.T) {
+	var refreshes int32
+	auth := NewRefreshingBearerAuth(func(ctx context.Context) (string, time.Time, error) {
+		n := atomic.AddInt32(&refreshes, 1)
+		// tok-1 expires immediately; later tokens are valid for an hour or more
+		return fmt.Sprintf("tok-%d", n), time.Now().Add(time.Duration(n-1) * time.Hour), nil
+	})
+
+	var rejected int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer tok-2" && atomic.CompareAndSwapInt32(&rejected, 0, 1) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code": "UNAUTHORIZED", "message": "token revoked"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status": %q}`, r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(&ClientConfig{BaseURL: srv.URL, Timeout: 5 * time.Second, SkipValidation: true, Authenticator: auth})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.makeRequest(ctx, op("Test"), "GET", "/health", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	// tok-1 has expired, tok-2 is rejected, tok-3 succeeds with the full body
+	body, err := client.makeRequest(ctx, op("Test"), "POST", "/nodes", map[string]interface{}{"labels": []string{"A"}}, nil)
+	if err != nil {
+		t.Fatalf("Expected request to succeed after refreshing, got %v", err)
+	}
+	if string(body) != `{"status": "Bearer tok-3"}` {
+		t.Errorf("Expected request with tok-3, got %s", body)
+	}
+
+	// tok-3 is cached
+	if _, err := client.makeRequest(ctx, op("Test"), "GET", "/health", nil, nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&refreshes); got != 3 {
+		t.Errorf("Expected 3 refreshes, got %d", got)
+	}
+}
+
+func TestAuthenticatorErrorIsReturned(t *testing.T) {
+	srv, _ := headerServer(t)
+	auth := AuthenticatorFunc(func(req *http.Request) error {
+		return fmt.Errorf("vault unavailable")
+	})
+	_, err := NewClient(&ClientConfig{BaseURL: srv.URL, Timeout: 5 * time.Second, Authenticator: auth})
+	if err == nil {
+		t.Fatal("Expected authentication failure, got nil")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCert(t, dir)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status": %q}`, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCert)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", srv.Certificate().Raw)
+
+	// Without a client certificate the handshake fails
+	_, err := NewClient(&ClientConfig{BaseURL: srv.URL, Timeout: 5 * time.Second, TLS: &TLSConfig{CAFile: caFile}})
+	if err == nil {
+		t.Error("Expected handshake to fail without a client certificate")
+	}
+
+	client, err := NewClient(&ClientConfig{
+		BaseURL: srv.URL,
+		Timeout: 5 * time.Second,
+		TLS:     &TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create mTLS client: %v", err)
+	}
+	body, err := client.makeRequest(context.Background(), op("Test"), "GET", "/health", nil, nil)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if string(body) != `{"status": "nendb-client"}` {
+		t.Errorf("Expected server to see the client certificate, got %s", body)
+	}
+}
+
+func TestTLSConfigValidation(t *testing.T) {
+	tests := map[string]*ClientConfig{
+		"missing CA":         {TLS: &TLSConfig{CAFile: "/does/not/exist.pem"}},
+		"cert without key":   {TLS: &TLSConfig{CertFile: "client.pem"}},
+		"custom HTTP client": {TLS: &TLSConfig{}, HTTPClient: &http.Client{}},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			config.BaseURL = "https://localhost:8443"
+			config.SkipValidation = true
+			if _, err := NewClient(config); err == nil {
+				t.Error("Expected configuration error, got nil")
+			}
+		})
+	}
+}
+
+// writeClientCert writes a self-signed client certificate and key to dir
+func writeClientCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nendb-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, cert
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// DefaultBatchSize is the number of items sent per batch request when
+// ClientConfig.BatchSize is not set
+const DefaultBatchSize = 500
+
+// UnassignedID marks an item in a batch that was not created
+const UnassignedID = -1
+
+// batchItemResult is a single entry of a batch creation response
+type batchItemResult struct {
+	Index int  `json:"index"`
+	ID    *int `json:"id,omitempty"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// batchResponse is the response body of the batch creation endpoints
+type batchResponse struct {
+	Results []batchItemResult `json:"results"`
+}
+
+// CreateNodes creates many nodes using as few round trips as possible.
+//
+// Nodes are sent in chunks of ClientConfig.BatchSize. The returned slice holds
+// the assigned node IDs in input order; items that failed hold UnassignedID.
+// If any item fails, the returned error is a *errors.NenDBBatchError listing
+// each failure by input index. A request-level failure aborts the remaining
+// chunks and is returned as-is along with the IDs assigned so far.
+func (c *NenDBClient) CreateNodes(ctx context.Context, nodes []types.GraphNode, opts ...CallOption) ([]int, error) {
+	items := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		if err := validateBatchItem(i, n.Properties); err != nil {
+			return nil, err
+		}
+		labels := n.Labels
+		if labels == nil {
+			labels = []string{}
+		}
+		items[i] = map[string]interface{}{
+			"labels":     labels,
+			"properties": n.Properties,
+		}
+	}
+	return c.createBatch(ctx, "CreateNodes", "/nodes/batch", "nodes", items, opts)
+}
+
+// CreateEdges creates many edges using as few round trips as possible.
+//
+// The ID field of each edge is ignored. Results and errors follow the same
+// rules as CreateNodes.
+func (c *NenDBClient) CreateEdges(ctx context.Context, edges []types.GraphEdge, opts ...CallOption) ([]int, error) {
+	items := make([]interface{}, len(edges))
+	for i, e := range edges {
+		if err := validateBatchItem(i, e.Properties); err != nil {
+			return nil, err
+		}
+		items[i] = map[string]interface{}{
+			"source":     e.Source,
+			"target":     e.Target,
+			"type":       e.Type,
+			"properties": e.Properties,
+		}
+	}
+	return c.createBatch(ctx, "CreateEdges", "/edges/batch", "edges", items, opts)
+}
+
+// validateBatchItem checks the properties of the item at index before
+// anything is sent
+func validateBatchItem(index int, properties map[string]interface{}) error {
+	if err := validateProperties(properties); err != nil {
+		err.Details["index"] = index
+		return err
+	}
+	return nil
+}
+
+// BatchSize returns the number of items sent per batch request
+func (c *NenDBClient) BatchSize() int {
+	if c.config.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return c.config.BatchSize
+}
+
+// createBatch posts items to endpoint in chunks and collects assigned IDs
+func (c *NenDBClient) createBatch(ctx context.Context, opName, endpoint, key string, items []interface{}, opts []CallOption) ([]int, error) {
+	ids := make([]int, len(items))
+	for i := range ids {
+		ids[i] = UnassignedID
+	}
+
+	batchSize := c.BatchSize()
+
+	// Every chunk is a distinct request and needs its own idempotency key
+	idempotencyKey := newCallOptions(opts).idempotencyKey
+
+	var failures []errors.BatchFailure
+	for start := 0; start < len(items); start += batchSize {
+		end := start + batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+
+		chunkOpts := opts
+		if idempotencyKey != "" && start > 0 {
+			chunkOpts = append(opts[:len(opts):len(opts)], WithIdempotencyKey(fmt.Sprintf("%s-%d", idempotencyKey, start/batchSize)))
+		}
+		respBody, err := c.makeRequest(ctx, op(opName), "POST", endpoint, map[string]interface{}{key: items[start:end]}, nil, chunkOpts...)
+		if err != nil {
+			return ids, err
+		}
+
+		var resp batchResponse
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return ids, errors.NewResponseError("Failed to parse batch response", map[string]interface{}{"error": err.Error()})
+		}
+
+		reported := make(map[int]bool, end-start)
+		for _, r := range resp.Results {
+			if r.Index < 0 || r.Index >= end-start {
+				continue
+			}
+			reported[r.Index] = true
+			switch {
+			case r.Error != nil:
+				failures = append(failures, errors.BatchFailure{
+					Index:   start + r.Index,
+					Code:    r.Error.Code,
+					Message: r.Error.Message,
+				})
+			case r.ID != nil:
+				ids[start+r.Index] = *r.ID
+			}
+		}
+		for i := 0; i < end-start; i++ {
+			if !reported[i] {
+				failures = append(failures, errors.BatchFailure{
+					Index:   start + i,
+					Message: "no result returned by server",
+				})
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return ids, errors.NewBatchError(fmt.Sprintf("%d of %d %s failed", len(failures), len(items), key), failures)
+	}
+	return ids, nil
+}
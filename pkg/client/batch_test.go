@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+func newTestClient(t *testing.T, srv *nendbtest.Server) *NenDBClient {
+	t.Helper()
+	config := DefaultConfig()
+	config.BaseURL = srv.URL
+	config.MaxRetries = 0
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestCreateNodesChunksAndPreservesOrder(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	client.config.BatchSize = 3
+
+	nodes := make([]types.GraphNode, 10)
+	for i := range nodes {
+		nodes[i] = types.GraphNode{
+			Labels:     []string{"Person"},
+			Properties: map[string]interface{}{"n": i},
+		}
+	}
+
+	ids, err := client.CreateNodes(context.Background(), nodes)
+	if err != nil {
+		t.Fatalf("Expected no error creating nodes, got %v", err)
+	}
+	if len(ids) != len(nodes) {
+		t.Fatalf("Expected %d IDs, got %d", len(nodes), len(ids))
+	}
+	for i, id := range ids {
+		node := srv.Node(id)
+		if node == nil {
+			t.Fatalf("Expected node %d to exist", id)
+		}
+		if node.Properties["n"] != json.Number(strconv.Itoa(i)) {
+			t.Errorf("Expected ID at index %d to map to n=%d, got %v", i, i, node.Properties["n"])
+		}
+	}
+}
+
+func TestCreateEdgesReportsPerItemFailures(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	client.config.BatchSize = 2
+
+	a := srv.AddNode(nil, nil)
+	b := srv.AddNode(nil, nil)
+
+	edges := []types.GraphEdge{
+		{Source: a.ID, Target: b.ID, Type: "KNOWS"},
+		{Source: a.ID, Target: 42, Type: "KNOWS"},
+		{Source: b.ID, Target: a.ID, Type: "KNOWS"},
+		{Source: b.ID, Target: a.ID},
+	}
+
+	ids, err := client.CreateEdges(context.Background(), edges)
+	batchErr, ok := err.(*errors.NenDBBatchError)
+	if !ok {
+		t.Fatalf("Expected *NenDBBatchError, got %T (%v)", err, err)
+	}
+	if len(batchErr.Failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %v", batchErr.Failures)
+	}
+	if batchErr.Failures[0].Index != 1 || batchErr.Failures[1].Index != 3 {
+		t.Errorf("Expected failures at indexes 1 and 3, got %v", batchErr.Failures)
+	}
+	if ids[1] != UnassignedID || ids[3] != UnassignedID {
+		t.Errorf("Expected failed items to be unassigned, got %v", ids)
+	}
+	if ids[0] == UnassignedID || ids[2] == UnassignedID {
+		t.Errorf("Expected successful items to have IDs, got %v", ids)
+	}
+	if len(srv.Edges()) != 2 {
+		t.Errorf("Expected 2 edges on server, got %d", len(srv.Edges()))
+	}
+}
+
+func TestCreateNodesEmpty(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	ids, err := client.CreateNodes(context.Background(), nil)
+	if err != nil {
+		t.Errorf("Expected no error for empty batch, got %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("Expected no IDs, got %v", ids)
+	}
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through while counting their failures
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests without contacting the server
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe requests through to test whether
+	// the server has recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerConfig configures the client's circuit breaker. Zero fields
+// take the defaults shown.
+//
+// The breaker counts every attempt, retries included. Network errors,
+// timeouts and 5xx responses are failures; other responses show that the
+// server is up and count as successes. Attempts cancelled by the caller are
+// not counted.
+type CircuitBreakerConfig struct {
+	// FailureRatio is the share of failed attempts, between 0 and 1, that
+	// opens the breaker (default 0.5)
+	FailureRatio float64
+	// MinRequests is the number of attempts within Window needed before the
+	// failure ratio is considered (default 10)
+	MinRequests int
+	// Window is the period over which attempts are counted while the breaker
+	// is closed; the counts are reset when it elapses (default 10s)
+	Window time.Duration
+	// Cooldown is how long the breaker stays open before it lets probe
+	// requests through (default 30s)
+	Cooldown time.Duration
+	// HalfOpenRequests is the number of probe requests allowed while
+	// half-open. The breaker closes once they all succeed and opens again as
+	// soon as one fails (default 1).
+	HalfOpenRequests int
+	// OnStateChange, when set, is called after every state change. It may be
+	// called from several goroutines and must not block.
+	OnStateChange func(from, to CircuitState)
+}
+
+// attemptOutcome is what an attempt tells the breaker about the server
+type attemptOutcome int
+
+const (
+	outcomeSuccess attemptOutcome = iota
+	outcomeFailure
+	// outcomeIgnored is an attempt that was abandoned by the caller or never
+	// sent
+	outcomeIgnored
+)
+
+// classifyAttempt returns the outcome of an attempt made under ctx
+func classifyAttempt(ctx context.Context, resp *http.Response, err error) attemptOutcome {
+	switch {
+	case err != nil && stderrors.Is(ctx.Err(), context.Canceled):
+		return outcomeIgnored
+	case err != nil || resp.StatusCode >= 500:
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// circuitBreaker implements CircuitBreakerConfig. A nil *circuitBreaker
+// admits every request.
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	state CircuitState
+	// generation changes with every state change, so that attempts started
+	// before it do not count towards the new state
+	generation uint64
+	// since is when the current state or counting window began
+	since     time.Time
+	requests  int
+	failures  int
+	probes    int
+	successes int
+}
+
+func newCircuitBreaker(config *CircuitBreakerConfig) (*circuitBreaker, error) {
+	if config == nil {
+		return nil, nil
+	}
+	c := *config
+	if c.FailureRatio < 0 || c.FailureRatio > 1 {
+		return nil, fmt.Errorf("failure ratio %v is not between 0 and 1", c.FailureRatio)
+	}
+	if c.FailureRatio == 0 {
+		c.FailureRatio = 0.5
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = 10
+	}
+	if c.Window <= 0 {
+		c.Window = 10 * time.Second
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = 30 * time.Second
+	}
+	if c.HalfOpenRequests <= 0 {
+		c.HalfOpenRequests = 1
+	}
+	b := &circuitBreaker{config: c, now: time.Now}
+	b.since = b.now()
+	return b, nil
+}
+
+// State returns the breaker's current state
+func (b *circuitBreaker) State() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allow reports whether an attempt may be sent. When it may, the returned
+// function must be called once with the attempt's outcome. Otherwise allow
+// returns how long the breaker stays open.
+func (b *circuitBreaker) allow() (func(attemptOutcome), time.Duration, bool) {
+	if b == nil {
+		return func(attemptOutcome) {}, 0, true
+	}
+	b.mu.Lock()
+	now := b.now()
+	from := b.state
+	switch b.state {
+	case CircuitClosed:
+		if now.Sub(b.since) >= b.config.Window {
+			b.since, b.requests, b.failures = now, 0, 0
+		}
+	case CircuitOpen:
+		if wait := b.config.Cooldown - now.Sub(b.since); wait > 0 {
+			b.mu.Unlock()
+			return nil, wait, false
+		}
+		b.setState(CircuitHalfOpen, now)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.config.HalfOpenRequests {
+			b.mu.Unlock()
+			b.notify(from, CircuitHalfOpen)
+			return nil, 0, false
+		}
+		b.probes++
+	}
+	generation, to := b.generation, b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+
+	return func(outcome attemptOutcome) { b.record(generation, outcome) }, 0, true
+}
+
+// record counts the outcome of an attempt allowed in generation
+func (b *circuitBreaker) record(generation uint64, outcome attemptOutcome) {
+	b.mu.Lock()
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	now := b.now()
+	from := b.state
+	switch b.state {
+	case CircuitClosed:
+		if outcome == outcomeIgnored {
+			break
+		}
+		b.requests++
+		if outcome == outcomeFailure {
+			b.failures++
+		}
+		if b.requests >= b.config.MinRequests && float64(b.failures) >= b.config.FailureRatio*float64(b.requests) {
+			b.setState(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		switch outcome {
+		case outcomeIgnored:
+			b.probes--
+		case outcomeFailure:
+			b.setState(CircuitOpen, now)
+		case outcomeSuccess:
+			b.successes++
+			if b.successes >= b.config.HalfOpenRequests {
+				b.setState(CircuitClosed, now)
+			}
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// setState moves the breaker to state and resets its counts; b.mu must be
+// held
+func (b *circuitBreaker) setState(state CircuitState, now time.Time) {
+	b.state = state
+	b.generation++
+	b.since = now
+	b.requests, b.failures, b.probes, b.successes = 0, 0, 0, 0
+}
+
+// notify reports a state change to OnStateChange; b.mu must not be held
+func (b *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(from, to)
+	}
+}
+
+// CircuitState returns the state of the client's circuit breaker, or
+// CircuitClosed when none is configured
+func (c *NenDBClient) CircuitState() CircuitState {
+	return c.breaker.State()
+}
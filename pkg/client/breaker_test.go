@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+)
+
+func TestCircuitBreakerStates(t *testing.T) {
+	var transitions []string
+	b, err := newCircuitBreaker(&CircuitBreakerConfig{
+		MinRequests:      4,
+		Window:           time.Minute,
+		Cooldown:         10 * time.Second,
+		HalfOpenRequests: 2,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	if err != nil {
+		t.Fatalf("newCircuitBreaker failed: %v", err)
+	}
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	attempt := func(outcome attemptOutcome) {
+		t.Helper()
+		done, _, ok := b.allow()
+		if !ok {
+			t.Fatalf("Expected the attempt to be allowed in state %v", b.State())
+		}
+		done(outcome)
+	}
+
+	// Failures in an elapsed window are forgotten
+	attempt(outcomeFailure)
+	attempt(outcomeFailure)
+	attempt(outcomeFailure)
+	now = now.Add(time.Minute)
+	attempt(outcomeFailure)
+	attempt(outcomeSuccess)
+	attempt(outcomeIgnored)
+	attempt(outcomeSuccess)
+	if b.State() != CircuitClosed {
+		t.Fatalf("Expected the breaker to stay closed, got %v", b.State())
+	}
+	stale, _, _ := b.allow()
+	attempt(outcomeFailure)
+	if b.State() != CircuitOpen {
+		t.Fatalf("Expected 2 failures in 4 attempts to open the breaker, got %v", b.State())
+	}
+
+	now = now.Add(4 * time.Second)
+	if _, wait, ok := b.allow(); ok || wait != 6*time.Second {
+		t.Errorf("Expected to be rejected for 6s, got %v, %v", ok, wait)
+	}
+
+	now = now.Add(6 * time.Second)
+	probe, _, ok := b.allow()
+	if !ok || b.State() != CircuitHalfOpen {
+		t.Fatalf("Expected a probe after the cooldown, got %v in state %v", ok, b.State())
+	}
+	stale(outcomeFailure)
+	attempt(outcomeIgnored)
+	probe2, _, ok := b.allow()
+	if !ok {
+		t.Fatal("Expected an abandoned probe to free its slot")
+	}
+	if _, wait, ok := b.allow(); ok || wait != 0 {
+		t.Errorf("Expected probes beyond HalfOpenRequests to be rejected, got %v, %v", ok, wait)
+	}
+	probe(outcomeSuccess)
+	probe2(outcomeSuccess)
+	if b.State() != CircuitClosed {
+		t.Fatalf("Expected successful probes to close the breaker, got %v", b.State())
+	}
+
+	for i := 0; i < 4; i++ {
+		attempt(outcomeFailure)
+	}
+	now = now.Add(10 * time.Second)
+	attempt(outcomeFailure)
+
+	want := []string{
+		"closed->open", "open->half-open", "half-open->closed",
+		"closed->open", "open->half-open", "half-open->open",
+	}
+	if !reflect.DeepEqual(transitions, want) {
+		t.Errorf("Expected transitions %q, got %q", want, transitions)
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"code": "UNAVAILABLE", "message": "down"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status": "healthy"}`)
+	}))
+	defer srv.Close()
+
+	var states []CircuitState
+	client, err := NewClient(&ClientConfig{
+		BaseURL:        srv.URL,
+		Timeout:        5 * time.Second,
+		MaxRetries:     10,
+		RetryDelay:     time.Millisecond,
+		SkipValidation: true,
+		CircuitBreaker: &CircuitBreakerConfig{
+			MinRequests: 3,
+			Cooldown:    50 * time.Millisecond,
+			OnStateChange: func(from, to CircuitState) {
+				states = append(states, to)
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	// The breaker opens after the third failed attempt and cuts the retries
+	// short
+	_, err = client.GetStatistics(ctx)
+	var openErr *errors.NenDBCircuitOpenError
+	var respErr *errors.NenDBResponseError
+	if !stderrors.As(err, &openErr) || !stderrors.As(err, &respErr) || respErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a circuit open error wrapping the 503, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts before the breaker opened, got %d", calls.Load())
+	}
+
+	_, err = client.GetNode(ctx, 1)
+	if !stderrors.As(err, &openErr) || openErr.RetryAfter <= 0 || openErr.Endpoint != "/nodes/1" {
+		t.Fatalf("Expected a circuit open error, got %v", err)
+	}
+	if calls.Load() != 3 || client.CircuitState() != CircuitOpen {
+		t.Errorf("Expected the open breaker to fail fast, got %d calls in state %v", calls.Load(), client.CircuitState())
+	}
+
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.GetStatistics(ctx); err != nil {
+		t.Fatalf("Expected the probe to succeed, got %v", err)
+	}
+	if want := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}; !reflect.DeepEqual(states, want) {
+		t.Errorf("Expected states %v, got %v", want, states)
+	}
+}
+
+func TestCircuitBreakerConfig(t *testing.T) {
+	_, err := NewClient(&ClientConfig{
+		SkipValidation: true,
+		CircuitBreaker: &CircuitBreakerConfig{FailureRatio: 1.5},
+	})
+	var validationErr *errors.NenDBValidationError
+	if !stderrors.As(err, &validationErr) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestClassifyAttempt(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		resp *http.Response
+		err  error
+		want attemptOutcome
+	}{
+		{"ok", context.Background(), &http.Response{StatusCode: 200}, nil, outcomeSuccess},
+		{"not found", context.Background(), &http.Response{StatusCode: 404}, nil, outcomeSuccess},
+		{"rate limited", context.Background(), &http.Response{StatusCode: 429}, nil, outcomeSuccess},
+		{"unavailable", context.Background(), &http.Response{StatusCode: 503}, nil, outcomeFailure},
+		{"refused", context.Background(), nil, stderrors.New("connection refused"), outcomeFailure},
+		{"timed out", expired, nil, context.DeadlineExceeded, outcomeFailure},
+		{"cancelled", cancelled, nil, context.Canceled, outcomeIgnored},
+	}
+	for _, tt := range tests {
+		if got := classifyAttempt(tt.ctx, tt.resp, tt.err); got != tt.want {
+			t.Errorf("%s: classifyAttempt = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
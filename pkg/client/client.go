@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/nen-co/nendb-go/pkg/errors"
@@ -23,6 +27,32 @@ type ClientConfig struct {
 	RetryDelay     time.Duration
 	SkipValidation bool
 	HTTPClient     *http.Client
+	BatchSize      int
+	// RetryPolicy decides which failed requests are retried. When nil,
+	// an ExponentialBackoff built from MaxRetries and RetryDelay is used.
+	RetryPolicy RetryPolicy
+	// Authenticator adds credentials to every request
+	Authenticator Authenticator
+	// TLS configures CA bundles and client certificates. It cannot be
+	// combined with HTTPClient; configure that client's transport instead.
+	TLS *TLSConfig
+	// Transport tunes connection pooling, dialing, HTTP/2 and proxies. When
+	// nil the TransportConfig defaults are used. Like TLS, it cannot be
+	// combined with HTTPClient.
+	Transport *TransportConfig
+	// Tracer, when set, traces every request and propagates the W3C trace
+	// context to the server
+	Tracer Tracer
+	// Meter, when set, records request latency, retries and errors
+	Meter Meter
+	// RateLimits, when set, throttles requests on the client side
+	RateLimits *RateLimits
+	// CircuitBreaker, when set, fails requests fast with a
+	// NenDBCircuitOpenError while the server keeps failing
+	CircuitBreaker *CircuitBreakerConfig
+	// Logger, when set, logs requests, attempts, retries and failures.
+	// Credentials and property values are redacted.
+	Logger *slog.Logger
 }
 
 // DefaultConfig returns a default client configuration
@@ -32,14 +62,25 @@ func DefaultConfig() *ClientConfig {
 		Timeout:    30 * time.Second,
 		MaxRetries: 3,
 		RetryDelay: 1 * time.Second,
+		BatchSize:  DefaultBatchSize,
 	}
 }
 
-// NenDBClient is the main client for interacting with NenDB
+// NenDBClient is the main client for interacting with NenDB. Every method
+// accepts CallOptions that customise that call alone.
 type NenDBClient struct {
-	config     *ClientConfig
-	httpClient *http.Client
-	baseURL    string
+	config      *ClientConfig
+	httpClient  *http.Client
+	baseURL     string
+	retryPolicy RetryPolicy
+	// serverTx records whether the server supports transactions
+	serverTx atomic.Int32
+	stats    *connStats
+	tel      *telemetry
+	limiter  *limiter
+	breaker  *circuitBreaker
+	// interceptors wrap every request, see Use
+	interceptors interceptors
 }
 
 // NewClient creates a new NenDB client
@@ -52,26 +93,67 @@ func NewClient(config *ClientConfig) (*NenDBClient, error) {
 	baseURL := strings.TrimRight(config.BaseURL, "/")
 
 	// Create HTTP client if not provided
+	stats := &connStats{}
 	httpClient := config.HTTPClient
+	if httpClient != nil && config.TLS != nil {
+		return nil, errors.NewValidationError("TLS cannot be combined with a custom HTTPClient", nil)
+	}
+	if httpClient != nil && config.Transport != nil {
+		return nil, errors.NewValidationError("Transport cannot be combined with a custom HTTPClient", nil)
+	}
 	if httpClient == nil {
+		transportConfig := config.Transport
+		if transportConfig == nil {
+			transportConfig = &TransportConfig{}
+		}
+		transport, err := transportConfig.Build()
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid transport configuration", map[string]interface{}{"error": err.Error()})
+		}
+		transport.DialContext = stats.trackDial(transport.DialContext)
+		if config.TLS != nil {
+			tlsConfig, err := config.TLS.Build()
+			if err != nil {
+				return nil, errors.NewValidationError("Invalid TLS configuration", map[string]interface{}{"error": err.Error()})
+			}
+			transport.TLSClientConfig = tlsConfig
+		}
 		httpClient = &http.Client{
-			Timeout: config.Timeout,
+			Timeout:   config.Timeout,
+			Transport: transport,
 		}
 	}
 
+	retryPolicy := config.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = NewExponentialBackoff(config.MaxRetries, config.RetryDelay)
+	}
+
+	breaker, err := newCircuitBreaker(config.CircuitBreaker)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid circuit breaker configuration", map[string]interface{}{"error": err.Error()})
+	}
+
 	client := &NenDBClient{
-		config:     config,
-		httpClient: httpClient,
-		baseURL:    baseURL,
+		config:      config,
+		httpClient:  httpClient,
+		baseURL:     baseURL,
+		retryPolicy: retryPolicy,
+		stats:       stats,
+		tel:         newTelemetry(config.Tracer, config.Meter, baseURL),
+		limiter:     newLimiter(config.RateLimits),
+		breaker:     breaker,
 	}
 
 	// Validate connection if not skipped
 	if !config.SkipValidation {
 		if err := client.Health(); err != nil {
-			return nil, errors.NewConnectionError(
+			connErr := errors.NewConnectionError(
 				fmt.Sprintf("Failed to connect to NenDB server at %s", baseURL),
 				map[string]interface{}{"error": err.Error()},
 			)
+			connErr.Err = err
+			return nil, connErr
 		}
 	}
 
@@ -79,30 +161,70 @@ func NewClient(config *ClientConfig) (*NenDBClient, error) {
 }
 
 // makeRequest performs an HTTP request with retry logic
-func (c *NenDBClient) makeRequest(ctx context.Context, method, endpoint string, data interface{}, params map[string]string) ([]byte, error) {
+func (c *NenDBClient) makeRequest(ctx context.Context, op operation, method, endpoint string, data interface{}, params map[string]string, opts ...CallOption) ([]byte, error) {
+	o := newCallOptions(opts)
+	ctx, cancel := o.context(ctx)
+	defer cancel()
+
+	req := &Request{
+		Operation: op.name,
+		Method:    method,
+		Endpoint:  endpoint,
+		Params:    params,
+		Payload:   data,
+		Header:    o.headers(method),
+	}
+	if txID, ok := ctx.Value(txKey{}).(string); ok {
+		req.Header.Set(TransactionHeader, txID)
+	}
+
+	ctx, call := c.tel.start(ctx, op, method, endpoint)
+	retryPolicy := o.retryPolicy(c.retryPolicy)
+	resp, err := c.wrap(func(ctx context.Context, req *Request) (*Response, error) {
+		log := startLog(ctx, c.config.Logger, req)
+		resp, err := c.send(ctx, call, log, req, retryPolicy)
+		log.end(ctx, resp, err)
+		return resp, err
+	})(ctx, req)
+	if err == nil && resp == nil {
+		err = errors.NewResponseError("Interceptor returned neither a response nor an error", map[string]interface{}{"operation": op.name})
+	}
+	call.end(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// send builds the HTTP request for req and performs its attempts
+func (c *NenDBClient) send(ctx context.Context, call *callTrace, log *callLog, r *Request, retryPolicy RetryPolicy) (*Response, error) {
+	method, endpoint := r.Method, r.Endpoint
+	class := classify(r)
+
 	// Build URL
 	requestURL := c.baseURL + endpoint
-	if len(params) > 0 {
+	if len(r.Params) > 0 {
 		u, err := url.Parse(requestURL)
 		if err != nil {
 			return nil, errors.NewValidationError("Invalid URL", map[string]interface{}{"url": requestURL, "error": err.Error()})
 		}
 		q := u.Query()
-		for key, value := range params {
+		for key, value := range r.Params {
 			q.Set(key, value)
 		}
 		u.RawQuery = q.Encode()
 		requestURL = u.String()
 	}
 
-	// Prepare request body
+	// Prepare request body. A bytes.Reader lets http.NewRequest set GetBody,
+	// so every attempt sends the full payload.
 	var body io.Reader
-	if data != nil {
-		jsonData, err := json.Marshal(data)
+	if r.Payload != nil {
+		jsonData, err := json.Marshal(r.Payload)
 		if err != nil {
 			return nil, errors.NewValidationError("Failed to marshal request data", map[string]interface{}{"error": err.Error()})
 		}
-		body = bytes.NewBuffer(jsonData)
+		body = bytes.NewReader(jsonData)
 	}
 
 	// Create request
@@ -111,78 +233,218 @@ func (c *NenDBClient) makeRequest(ctx context.Context, method, endpoint string,
 		return nil, errors.NewValidationError("Failed to create request", map[string]interface{}{"error": err.Error()})
 	}
 
-	// Set headers
-	if data != nil {
+	// Set headers; those on r take precedence over the defaults
+	if r.Payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 	req.Header.Set("User-Agent", "nendb-go-driver/0.1.0")
+	call.inject(req.Header)
+	for key, values := range r.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
 
-	// Perform request with retries
+	// Perform request, retrying as the retry policy allows
 	var lastErr error
-	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
-		if attempt > 0 {
-			time.Sleep(c.config.RetryDelay * time.Duration(attempt))
+	reauthenticated := false
+	for attempt := 1; ; attempt++ {
+		// Every request after the first needs a fresh body
+		if attempt > 1 || reauthenticated {
+			if req, err = rewind(req); err != nil {
+				return nil, errors.NewValidationError("Failed to rebuild request body for retry", map[string]interface{}{"error": err.Error()})
+			}
 		}
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
+		if err := c.authenticate(req, method, endpoint); err != nil {
+			return nil, err
 		}
 
-		defer resp.Body.Close()
-
-		// Read response body
-		respBody, err := io.ReadAll(resp.Body)
+		done, wait, ok := c.breaker.allow()
+		if !ok {
+			return nil, circuitOpenError(wait, method, endpoint, lastErr)
+		}
+		release, err := c.limiter.acquire(ctx, class)
 		if err != nil {
+			done(outcomeIgnored)
+			timeoutErr := errors.NewTimeoutError("Request cancelled while waiting for the rate limiter", map[string]interface{}{"error": err.Error()})
+			timeoutErr.Method, timeoutErr.Endpoint, timeoutErr.Err = method, endpoint, err
+			return nil, timeoutErr
+		}
+		attemptStart := time.Now()
+		resp, respBody, err := c.do(req)
+		release()
+		done(classifyAttempt(ctx, resp, err))
+		call.attempt(resp)
+		log.attempt(ctx, attempt, resp, err, time.Since(attemptStart))
+		switch {
+		case err != nil:
 			lastErr = err
-			continue
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody, Attempts: attempt}, nil
+		default:
+			lastErr = responseError(resp, respBody)
 		}
 
-		// Check response status
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return respBody, nil
+		// Rejected credentials are refreshed and tried once more, without
+		// counting against the retry policy
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			if inv, ok := c.config.Authenticator.(invalidator); ok {
+				inv.Invalidate()
+				reauthenticated = true
+				attempt--
+				continue
+			}
 		}
 
-		// Handle error responses
-		if resp.StatusCode >= 400 {
-			var errorResp map[string]interface{}
-			if json.Unmarshal(respBody, &errorResp) == nil {
-				message := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status)
-				if msg, ok := errorResp["message"].(string); ok {
-					message = msg
-				}
-				return nil, errors.NewResponseError(message, errorResp)
-			}
-			return nil, errors.NewResponseError(fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status), nil)
+		delay, retry := retryPolicy.NextRetry(attempt, retryMethod(req), resp, err)
+		if !retry {
+			break
+		}
+		call.retry(ctx)
+		log.retry(ctx, attempt, delay, lastErr)
+		if err := sleepContext(ctx, delay); err != nil {
+			timeoutErr := errors.NewTimeoutError("Request cancelled while waiting to retry", map[string]interface{}{"error": err.Error(), "last_error": lastErr.Error()})
+			timeoutErr.Method, timeoutErr.Endpoint, timeoutErr.Err = method, endpoint, err
+			return nil, timeoutErr
 		}
+	}
 
-		// For 3xx status codes, continue with retry
-		lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if respErr, ok := lastErr.(*errors.NenDBResponseError); ok {
+		respErr.Method, respErr.Endpoint = method, endpoint
+		return nil, respErr
 	}
+	return nil, transportError(lastErr, method, endpoint)
+}
 
-	// All retries exhausted
+// circuitOpenError reports a request rejected by the circuit breaker, which
+// stays open for wait. lastErr is the failure of the request's previous
+// attempt, if any.
+func circuitOpenError(wait time.Duration, method, endpoint string, lastErr error) *errors.NenDBCircuitOpenError {
+	details := map[string]interface{}{"retry_after": wait.String()}
 	if lastErr != nil {
-		return nil, errors.NewTimeoutError("Request failed after all retries", map[string]interface{}{"error": lastErr.Error()})
+		details["last_error"] = lastErr.Error()
+	}
+	openErr := errors.NewCircuitOpenError("Circuit breaker is open; request not sent", details)
+	openErr.RetryAfter = wait
+	openErr.Method, openErr.Endpoint, openErr.Err = method, endpoint, lastErr
+	return openErr
+}
+
+// validateProperties rejects property values the server cannot store
+func validateProperties(properties map[string]interface{}) *errors.NenDBValidationError {
+	err := types.ValidateProperties(properties)
+	if err == nil {
+		return nil
+	}
+	details := map[string]interface{}{"error": err.Error()}
+	var propErr *types.PropertyError
+	if stderrors.As(err, &propErr) {
+		details["property"] = propErr.Path
+		details["error"] = propErr.Reason
 	}
+	return errors.NewValidationError("Invalid property value", details)
+}
+
+// transportError classifies a failure to get any response from the server
+func transportError(err error, method, endpoint string) error {
+	details := map[string]interface{}{"error": err.Error()}
+	var netErr net.Error
+	if stderrors.Is(err, context.DeadlineExceeded) || (stderrors.As(err, &netErr) && netErr.Timeout()) {
+		timeoutErr := errors.NewTimeoutError("Request failed after all retries", details)
+		timeoutErr.Method, timeoutErr.Endpoint, timeoutErr.Err = method, endpoint, err
+		return timeoutErr
+	}
+	connErr := errors.NewConnectionError("Request failed after all retries", details)
+	connErr.Method, connErr.Endpoint, connErr.Err = method, endpoint, err
+	return connErr
+}
+
+// authenticate applies the configured Authenticator to req
+func (c *NenDBClient) authenticate(req *http.Request, method, endpoint string) error {
+	if c.config.Authenticator == nil {
+		return nil
+	}
+	if err := c.config.Authenticator.Authenticate(req); err != nil {
+		authErr := errors.NewConnectionError("Failed to authenticate request", map[string]interface{}{"error": err.Error()})
+		authErr.Code, authErr.Method, authErr.Endpoint, authErr.Err = "UNAUTHORIZED", method, endpoint, err
+		return authErr
+	}
+	return nil
+}
+
+// do sends a single attempt and reads and closes the response body before
+// returning, so no connection is held across retries. On error the response
+// is nil.
+func (c *NenDBClient) do(req *http.Request) (*http.Response, []byte, error) {
+	c.stats.requests.Add(1)
+	c.stats.inFlight.Add(1)
+	defer c.stats.inFlight.Add(-1)
+	ctx, release := c.stats.trace(req.Context())
+	defer release()
+
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
 
-	return nil, errors.NewTimeoutError("Request failed after all retries", nil)
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, respBody, nil
+}
+
+// rewind returns a copy of req with a fresh body for the next attempt
+func rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body cannot be replayed")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// responseError converts a non-2xx response into a NenDBResponseError
+func responseError(resp *http.Response, body []byte) *errors.NenDBResponseError {
+	var errorResp map[string]interface{}
+	if json.Unmarshal(body, &errorResp) != nil {
+		errorResp = nil
+	}
+
+	message := fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	if msg, ok := errorResp["message"].(string); ok {
+		message = msg
+	}
+	code, _ := errorResp["code"].(string)
+
+	err := errors.NewStatusError(resp.StatusCode, code, message, errorResp)
+	err.RequestID = resp.Header.Get("X-Request-Id")
+	if id, ok := errorResp["request_id"].(string); ok && err.RequestID == "" {
+		err.RequestID = id
+	}
+	return err
 }
 
 // Health checks the health of the NenDB server
-func (c *NenDBClient) Health() error {
+func (c *NenDBClient) Health(opts ...CallOption) error {
 	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
 	defer cancel()
 
-	_, err := c.makeRequest(ctx, "GET", "/health", nil, nil)
+	_, err := c.makeRequest(ctx, op("Health"), "GET", "/health", nil, nil, opts...)
 	return err
 }
 
 // GetNode retrieves a node by ID
-func (c *NenDBClient) GetNode(ctx context.Context, nodeID int) (*types.GraphNode, error) {
+func (c *NenDBClient) GetNode(ctx context.Context, nodeID int, opts ...CallOption) (*types.GraphNode, error) {
 	endpoint := fmt.Sprintf("/nodes/%d", nodeID)
 	
-	respBody, err := c.makeRequest(ctx, "GET", endpoint, nil, nil)
+	respBody, err := c.makeRequest(ctx, op("GetNode", nodeAttr(nodeID)), "GET", endpoint, nil, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -196,13 +458,16 @@ func (c *NenDBClient) GetNode(ctx context.Context, nodeID int) (*types.GraphNode
 }
 
 // CreateNode creates a new node
-func (c *NenDBClient) CreateNode(ctx context.Context, labels []string, properties map[string]interface{}) (*types.GraphNode, error) {
+func (c *NenDBClient) CreateNode(ctx context.Context, labels []string, properties map[string]interface{}, opts ...CallOption) (*types.GraphNode, error) {
+	if err := validateProperties(properties); err != nil {
+		return nil, err
+	}
 	data := map[string]interface{}{
 		"labels":     labels,
-		"properties": properties,
+		"properties": types.Properties(properties),
 	}
 
-	respBody, err := c.makeRequest(ctx, "POST", "/nodes", data, nil)
+	respBody, err := c.makeRequest(ctx, op("CreateNode"), "POST", "/nodes", data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -216,14 +481,17 @@ func (c *NenDBClient) CreateNode(ctx context.Context, labels []string, propertie
 }
 
 // UpdateNode updates an existing node
-func (c *NenDBClient) UpdateNode(ctx context.Context, nodeID int, labels []string, properties map[string]interface{}) (*types.GraphNode, error) {
+func (c *NenDBClient) UpdateNode(ctx context.Context, nodeID int, labels []string, properties map[string]interface{}, opts ...CallOption) (*types.GraphNode, error) {
+	if err := validateProperties(properties); err != nil {
+		return nil, err
+	}
 	endpoint := fmt.Sprintf("/nodes/%d", nodeID)
 	data := map[string]interface{}{
 		"labels":     labels,
-		"properties": properties,
+		"properties": types.Properties(properties),
 	}
 
-	respBody, err := c.makeRequest(ctx, "PUT", endpoint, data, nil)
+	respBody, err := c.makeRequest(ctx, op("UpdateNode", nodeAttr(nodeID)), "PUT", endpoint, data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -237,17 +505,17 @@ func (c *NenDBClient) UpdateNode(ctx context.Context, nodeID int, labels []strin
 }
 
 // DeleteNode deletes a node by ID
-func (c *NenDBClient) DeleteNode(ctx context.Context, nodeID int) error {
+func (c *NenDBClient) DeleteNode(ctx context.Context, nodeID int, opts ...CallOption) error {
 	endpoint := fmt.Sprintf("/nodes/%d", nodeID)
-	_, err := c.makeRequest(ctx, "DELETE", endpoint, nil, nil)
+	_, err := c.makeRequest(ctx, op("DeleteNode", nodeAttr(nodeID)), "DELETE", endpoint, nil, nil, opts...)
 	return err
 }
 
 // GetEdge retrieves an edge by ID
-func (c *NenDBClient) GetEdge(ctx context.Context, edgeID int) (*types.GraphEdge, error) {
+func (c *NenDBClient) GetEdge(ctx context.Context, edgeID int, opts ...CallOption) (*types.GraphEdge, error) {
 	endpoint := fmt.Sprintf("/edges/%d", edgeID)
 	
-	respBody, err := c.makeRequest(ctx, "GET", endpoint, nil, nil)
+	respBody, err := c.makeRequest(ctx, op("GetEdge", edgeAttr(edgeID)), "GET", endpoint, nil, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -261,15 +529,18 @@ func (c *NenDBClient) GetEdge(ctx context.Context, edgeID int) (*types.GraphEdge
 }
 
 // CreateEdge creates a new edge
-func (c *NenDBClient) CreateEdge(ctx context.Context, source, target int, edgeType string, properties map[string]interface{}) (*types.GraphEdge, error) {
+func (c *NenDBClient) CreateEdge(ctx context.Context, source, target int, edgeType string, properties map[string]interface{}, opts ...CallOption) (*types.GraphEdge, error) {
+	if err := validateProperties(properties); err != nil {
+		return nil, err
+	}
 	data := map[string]interface{}{
 		"source":     source,
 		"target":     target,
 		"type":       edgeType,
-		"properties": properties,
+		"properties": types.Properties(properties),
 	}
 
-	respBody, err := c.makeRequest(ctx, "POST", "/edges", data, nil)
+	respBody, err := c.makeRequest(ctx, op("CreateEdge", pathAttrs(source, target)...), "POST", "/edges", data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -283,14 +554,17 @@ func (c *NenDBClient) CreateEdge(ctx context.Context, source, target int, edgeTy
 }
 
 // UpdateEdge updates an existing edge
-func (c *NenDBClient) UpdateEdge(ctx context.Context, edgeID int, edgeType string, properties map[string]interface{}) (*types.GraphEdge, error) {
+func (c *NenDBClient) UpdateEdge(ctx context.Context, edgeID int, edgeType string, properties map[string]interface{}, opts ...CallOption) (*types.GraphEdge, error) {
+	if err := validateProperties(properties); err != nil {
+		return nil, err
+	}
 	endpoint := fmt.Sprintf("/edges/%d", edgeID)
 	data := map[string]interface{}{
 		"type":       edgeType,
-		"properties": properties,
+		"properties": types.Properties(properties),
 	}
 
-	respBody, err := c.makeRequest(ctx, "PUT", endpoint, data, nil)
+	respBody, err := c.makeRequest(ctx, op("UpdateEdge", edgeAttr(edgeID)), "PUT", endpoint, data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -304,21 +578,21 @@ func (c *NenDBClient) UpdateEdge(ctx context.Context, edgeID int, edgeType strin
 }
 
 // DeleteEdge deletes an edge by ID
-func (c *NenDBClient) DeleteEdge(ctx context.Context, edgeID int) error {
+func (c *NenDBClient) DeleteEdge(ctx context.Context, edgeID int, opts ...CallOption) error {
 	endpoint := fmt.Sprintf("/edges/%d", edgeID)
-	_, err := c.makeRequest(ctx, "DELETE", endpoint, nil, nil)
+	_, err := c.makeRequest(ctx, op("DeleteEdge", edgeAttr(edgeID)), "DELETE", endpoint, nil, nil, opts...)
 	return err
 }
 
 // RunBFS runs the BFS algorithm
-func (c *NenDBClient) RunBFS(ctx context.Context, startNode, targetNode int, maxDepth int) (*types.BFSResult, error) {
+func (c *NenDBClient) RunBFS(ctx context.Context, startNode, targetNode int, maxDepth int, opts ...CallOption) (*types.BFSResult, error) {
 	data := map[string]interface{}{
 		"start_node": startNode,
 		"target_node": targetNode,
 		"max_depth":  maxDepth,
 	}
 
-	respBody, err := c.makeRequest(ctx, "POST", "/algorithms/bfs", data, nil)
+	respBody, err := c.makeRequest(ctx, op("RunBFS", pathAttrs(startNode, targetNode)...), "POST", "/algorithms/bfs", data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -332,13 +606,13 @@ func (c *NenDBClient) RunBFS(ctx context.Context, startNode, targetNode int, max
 }
 
 // RunDijkstra runs the Dijkstra shortest path algorithm
-func (c *NenDBClient) RunDijkstra(ctx context.Context, startNode, targetNode int) (*types.DijkstraResult, error) {
+func (c *NenDBClient) RunDijkstra(ctx context.Context, startNode, targetNode int, opts ...CallOption) (*types.DijkstraResult, error) {
 	data := map[string]interface{}{
 		"start_node": startNode,
 		"target_node": targetNode,
 	}
 
-	respBody, err := c.makeRequest(ctx, "POST", "/algorithms/dijkstra", data, nil)
+	respBody, err := c.makeRequest(ctx, op("RunDijkstra", pathAttrs(startNode, targetNode)...), "POST", "/algorithms/dijkstra", data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -352,13 +626,13 @@ func (c *NenDBClient) RunDijkstra(ctx context.Context, startNode, targetNode int
 }
 
 // RunPageRank runs the PageRank algorithm
-func (c *NenDBClient) RunPageRank(ctx context.Context, maxIterations int, tolerance float64) (*types.PageRankResult, error) {
+func (c *NenDBClient) RunPageRank(ctx context.Context, maxIterations int, tolerance float64, opts ...CallOption) (*types.PageRankResult, error) {
 	data := map[string]interface{}{
 		"max_iterations": maxIterations,
 		"tolerance":      tolerance,
 	}
 
-	respBody, err := c.makeRequest(ctx, "POST", "/algorithms/pagerank", data, nil)
+	respBody, err := c.makeRequest(ctx, op("RunPageRank"), "POST", "/algorithms/pagerank", data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -372,19 +646,20 @@ func (c *NenDBClient) RunPageRank(ctx context.Context, maxIterations int, tolera
 }
 
 // Query executes a custom Cypher-like query
-func (c *NenDBClient) Query(ctx context.Context, query string, params map[string]interface{}) (interface{}, error) {
+func (c *NenDBClient) Query(ctx context.Context, query string, params map[string]interface{}, opts ...CallOption) (*Result, error) {
 	data := map[string]interface{}{
 		"query":  query,
-		"params": params,
+		"params": types.Properties(params),
 	}
 
-	respBody, err := c.makeRequest(ctx, "POST", "/query", data, nil)
+	start := time.Now()
+	respBody, err := c.makeRequest(ctx, op("Query"), "POST", "/query", data, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	var result interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
+	result, err := parseResult(query, respBody, time.Since(start))
+	if err != nil {
 		return nil, errors.NewResponseError("Failed to parse query result", map[string]interface{}{"error": err.Error()})
 	}
 
@@ -392,8 +667,8 @@ func (c *NenDBClient) Query(ctx context.Context, query string, params map[string
 }
 
 // GetStatistics retrieves database statistics
-func (c *NenDBClient) GetStatistics(ctx context.Context) (map[string]interface{}, error) {
-	respBody, err := c.makeRequest(ctx, "GET", "/statistics", nil, nil)
+func (c *NenDBClient) GetStatistics(ctx context.Context, opts ...CallOption) (map[string]interface{}, error) {
+	respBody, err := c.makeRequest(ctx, op("GetStatistics"), "GET", "/statistics", nil, nil, opts...)
 	if err != nil {
 		return nil, err
 	}
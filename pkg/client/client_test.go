@@ -2,9 +2,15 @@ package client
 
 import (
 	"context"
+	stderrors "errors"
+	"math"
 	"net/http"
 	"testing"
 	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+	"github.com/nen-co/nendb-go/pkg/types"
 )
 
 func TestClientConfig(t *testing.T) {
@@ -94,6 +100,23 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientValidatesAgainstServer(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	// Health validation should succeed against a live server
+	client, err := NewClient(&ClientConfig{
+		BaseURL: srv.URL,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error creating client against test server, got %v", err)
+	}
+	if err := client.Health(); err != nil {
+		t.Errorf("Expected healthy server, got %v", err)
+	}
+}
+
 func TestClientBaseURL(t *testing.T) {
 	// Test with trailing slash
 	config := &ClientConfig{
@@ -150,3 +173,60 @@ func TestContextTimeout(t *testing.T) {
 		t.Error("Expected context to be done after timeout")
 	}
 }
+
+func TestTypedPropertiesRoundTrip(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	born := time.Date(1990, 4, 1, 12, 30, 0, 0, time.UTC)
+	created, err := client.CreateNode(ctx, []string{"Person"}, map[string]interface{}{
+		"id":    int64(math.MaxInt64),
+		"score": 2.0,
+		"born":  born,
+		"ttl":   90 * time.Second,
+		"home":  types.GeoPoint{Lat: 38.7, Lon: -9.1},
+	})
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+
+	node, err := client.GetNode(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if id, ok := node.Properties.Int64("id"); !ok || id != math.MaxInt64 {
+		t.Errorf("Expected int64 to survive the round trip, got %v", node.Properties["id"])
+	}
+	if score, ok := node.Properties["score"].(float64); !ok || score != 2 {
+		t.Errorf("Expected score to stay a float64, got %#v", node.Properties["score"])
+	}
+	if got, ok := node.Properties.Time("born"); !ok || !got.Equal(born) {
+		t.Errorf("Expected born %v, got %#v", born, node.Properties["born"])
+	}
+	if ttl, ok := node.Properties.Duration("ttl"); !ok || ttl != 90*time.Second {
+		t.Errorf("Expected ttl 90s, got %#v", node.Properties["ttl"])
+	}
+	if home, ok := node.Properties.Point("home"); !ok || home.Lat != 38.7 {
+		t.Errorf("Expected home point, got %#v", node.Properties["home"])
+	}
+}
+
+func TestInvalidPropertiesRejectedBeforeSending(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	before := srv.Requests()
+
+	_, err := client.CreateNode(context.Background(), []string{"Person"}, map[string]interface{}{
+		"tags": []interface{}{"ok", func() {}},
+	})
+	var valErr *errors.NenDBValidationError
+	if !stderrors.As(err, &valErr) || valErr.Details["property"] != "tags[1]" {
+		t.Fatalf("Expected validation error for tags[1], got %v", err)
+	}
+	if srv.Requests() != before {
+		t.Error("Expected no request to be sent for invalid properties")
+	}
+}
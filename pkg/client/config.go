@@ -0,0 +1,245 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+)
+
+// EnvPrefix prefixes every environment variable read by ConfigFromEnv. A
+// setting such as max_retries is read from NENDB_MAX_RETRIES.
+const EnvPrefix = "NENDB_"
+
+// DefaultProfile is the profile used when none is named
+const DefaultProfile = "default"
+
+// settingKeys lists the settings understood in profile files and, upper-cased
+// with EnvPrefix, in the environment
+var settingKeys = []string{
+	"url",
+	"timeout",
+	"max_retries",
+	"retry_delay",
+	"skip_validation",
+	"batch_size",
+	"api_key",
+	"api_key_header",
+	"token",
+	"username",
+	"password",
+	"ca_cert",
+	"client_cert",
+	"client_key",
+	"server_name",
+	"insecure",
+	"max_idle_conns",
+	"max_idle_conns_per_host",
+	"max_conns_per_host",
+	"idle_conn_timeout",
+	"keep_alive",
+	"dial_timeout",
+	"disable_http2",
+	"proxy",
+	"max_in_flight",
+	"read_rate",
+	"write_rate",
+	"algorithm_rate",
+}
+
+// ConfigFromEnv returns DefaultConfig overridden by NENDB_* environment
+// variables, for example NENDB_URL, NENDB_TIMEOUT=60s, NENDB_MAX_RETRIES=5
+// and NENDB_API_KEY.
+func ConfigFromEnv() (*ClientConfig, error) {
+	config, err := configFromSettings(envSettings())
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadProfile reads the named profile from a YAML, TOML or JSON file, chosen
+// by extension, and returns DefaultConfig overridden by the profile and then
+// by NENDB_* environment variables. An empty name selects DefaultProfile.
+//
+// Each top-level key of the file names a profile:
+//
+//	dev:
+//	  url: http://localhost:8080
+//	prod:
+//	  url: https://nendb.example.com
+//	  timeout: 10s
+//	  token: ...
+func LoadProfile(path, name string) (*ClientConfig, error) {
+	profiles, err := readProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, errors.NewValidationError(fmt.Sprintf("Profile %q not found in %s", name, path), map[string]interface{}{"profiles": names})
+	}
+
+	for key, value := range envSettings() {
+		profile[key] = value
+	}
+	config, settingsErr := configFromSettings(profile)
+	if settingsErr != nil {
+		settingsErr.Details["profile"] = name
+		settingsErr.Details["file"] = path
+		return nil, settingsErr
+	}
+	return config, nil
+}
+
+// DefaultProfilePath returns the first existing config.yaml, config.yml,
+// config.toml or config.json in the user's nendb config directory, or an
+// empty string if there is none. NENDB_CONFIG takes precedence when set.
+func DefaultProfilePath() string {
+	if path := os.Getenv(EnvPrefix + "CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml", "config.json"} {
+		path := filepath.Join(dir, "nendb", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// envSettings collects the settings present in the environment
+func envSettings() map[string]string {
+	settings := make(map[string]string)
+	for _, key := range settingKeys {
+		if value, ok := os.LookupEnv(EnvPrefix + strings.ToUpper(key)); ok {
+			settings[key] = value
+		}
+	}
+	return settings
+}
+
+// configFromSettings applies settings on top of DefaultConfig
+func configFromSettings(settings map[string]string) (*ClientConfig, *errors.NenDBValidationError) {
+	config := DefaultConfig()
+	var transport TransportConfig
+	useTransport := false
+	var limits RateLimits
+	useLimits := false
+	known := make(map[string]bool, len(settingKeys))
+	for _, key := range settingKeys {
+		known[key] = true
+	}
+
+	for key, value := range settings {
+		if !known[key] {
+			return nil, errors.NewValidationError(fmt.Sprintf("Unknown setting %q", key), map[string]interface{}{"settings": settingKeys})
+		}
+
+		var err error
+		switch key {
+		case "url":
+			config.BaseURL = value
+		case "timeout":
+			config.Timeout, err = time.ParseDuration(value)
+		case "max_retries":
+			config.MaxRetries, err = strconv.Atoi(value)
+		case "retry_delay":
+			config.RetryDelay, err = time.ParseDuration(value)
+		case "skip_validation":
+			config.SkipValidation, err = strconv.ParseBool(value)
+		case "batch_size":
+			config.BatchSize, err = strconv.Atoi(value)
+		case "max_idle_conns":
+			transport.MaxIdleConns, err = strconv.Atoi(value)
+			useTransport = true
+		case "max_idle_conns_per_host":
+			transport.MaxIdleConnsPerHost, err = strconv.Atoi(value)
+			useTransport = true
+		case "max_conns_per_host":
+			transport.MaxConnsPerHost, err = strconv.Atoi(value)
+			useTransport = true
+		case "idle_conn_timeout":
+			transport.IdleConnTimeout, err = time.ParseDuration(value)
+			useTransport = true
+		case "keep_alive":
+			transport.KeepAlive, err = time.ParseDuration(value)
+			useTransport = true
+		case "dial_timeout":
+			transport.DialTimeout, err = time.ParseDuration(value)
+			useTransport = true
+		case "disable_http2":
+			transport.DisableHTTP2, err = strconv.ParseBool(value)
+			useTransport = true
+		case "proxy":
+			transport.ProxyURL = value
+			useTransport = true
+		case "max_in_flight":
+			limits.MaxInFlight, err = strconv.Atoi(value)
+			useLimits = true
+		case "read_rate":
+			limits.Reads.Rate, err = strconv.ParseFloat(value, 64)
+			useLimits = true
+		case "write_rate":
+			limits.Writes.Rate, err = strconv.ParseFloat(value, 64)
+			useLimits = true
+		case "algorithm_rate":
+			limits.Algorithms.Rate, err = strconv.ParseFloat(value, 64)
+			useLimits = true
+		}
+		if err != nil {
+			return nil, errors.NewValidationError(fmt.Sprintf("Invalid value for setting %q", key), map[string]interface{}{"value": value, "error": err.Error()})
+		}
+	}
+
+	switch {
+	case settings["api_key"] != "":
+		config.Authenticator = &APIKeyAuth{Header: settings["api_key_header"], Key: settings["api_key"]}
+	case settings["token"] != "":
+		config.Authenticator = NewBearerAuth(settings["token"])
+	case settings["username"] != "":
+		config.Authenticator = &BasicAuth{Username: settings["username"], Password: settings["password"]}
+	}
+
+	tlsConfig := TLSConfig{
+		CAFile:     settings["ca_cert"],
+		CertFile:   settings["client_cert"],
+		KeyFile:    settings["client_key"],
+		ServerName: settings["server_name"],
+	}
+	if value := settings["insecure"]; value != "" {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.NewValidationError(`Invalid value for setting "insecure"`, map[string]interface{}{"value": value, "error": err.Error()})
+		}
+		tlsConfig.InsecureSkipVerify = insecure
+	}
+	if tlsConfig != (TLSConfig{}) {
+		config.TLS = &tlsConfig
+	}
+	if useTransport {
+		config.Transport = &transport
+	}
+	if useLimits {
+		config.RateLimits = &limits
+	}
+
+	return config, nil
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("NENDB_URL", "http://nendb.test:9000")
+	t.Setenv("NENDB_TIMEOUT", "45s")
+	t.Setenv("NENDB_MAX_RETRIES", "7")
+	t.Setenv("NENDB_TOKEN", "tok")
+	t.Setenv("NENDB_CA_CERT", "/etc/nendb/ca.pem")
+	t.Setenv("NENDB_MAX_IDLE_CONNS_PER_HOST", "64")
+	t.Setenv("NENDB_DISABLE_HTTP2", "true")
+	t.Setenv("NENDB_WRITE_RATE", "2.5")
+	t.Setenv("NENDB_MAX_IN_FLIGHT", "8")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+	if config.BaseURL != "http://nendb.test:9000" || config.Timeout != 45*time.Second || config.MaxRetries != 7 {
+		t.Errorf("Unexpected config: %+v", config)
+	}
+	if config.RetryDelay != DefaultConfig().RetryDelay {
+		t.Errorf("Expected unset values to keep defaults, got RetryDelay %v", config.RetryDelay)
+	}
+	if _, ok := config.Authenticator.(*BearerAuth); !ok {
+		t.Errorf("Expected bearer authenticator, got %T", config.Authenticator)
+	}
+	if config.TLS == nil || config.TLS.CAFile != "/etc/nendb/ca.pem" {
+		t.Errorf("Expected TLS CA file from environment, got %+v", config.TLS)
+	}
+	if config.Transport == nil || config.Transport.MaxIdleConnsPerHost != 64 || !config.Transport.DisableHTTP2 {
+		t.Errorf("Expected transport settings from environment, got %+v", config.Transport)
+	}
+	if config.RateLimits == nil || config.RateLimits.Writes.Rate != 2.5 || config.RateLimits.MaxInFlight != 8 {
+		t.Errorf("Expected rate limits from environment, got %+v", config.RateLimits)
+	}
+
+	t.Setenv("NENDB_TIMEOUT", "soon")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("Expected error for invalid NENDB_TIMEOUT, got nil")
+	}
+}
+
+const yamlProfiles = `# NenDB profiles
+dev:
+  url: http://localhost:8080
+prod:
+  url: "https://nendb.example.com"   # production
+  timeout: 10s
+  max_retries: 5
+  api_key: 'k#1'
+  api_key_header: X-NenDB-Key
+  insecure: false
+`
+
+const tomlProfiles = `# NenDB profiles
+[dev]
+url = "http://localhost:8080"
+
+[prod]
+url = "https://nendb.example.com" # production
+timeout = "10s"
+max_retries = 5
+api_key = 'k#1'
+api_key_header = "X-NenDB-Key"
+insecure = false
+`
+
+const jsonProfiles = `{
+  "dev": {"url": "http://localhost:8080"},
+  "prod": {
+    "url": "https://nendb.example.com",
+    "timeout": "10s",
+    "max_retries": 5,
+    "api_key": "k#1",
+    "api_key_header": "X-NenDB-Key",
+    "insecure": false
+  }
+}`
+
+func TestLoadProfileFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": yamlProfiles,
+		"config.toml": tomlProfiles,
+		"config.json": jsonProfiles,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadProfile(path, "prod")
+			if err != nil {
+				t.Fatalf("LoadProfile failed: %v", err)
+			}
+			if config.BaseURL != "https://nendb.example.com" || config.Timeout != 10*time.Second || config.MaxRetries != 5 {
+				t.Errorf("Unexpected config: %+v", config)
+			}
+			auth, ok := config.Authenticator.(*APIKeyAuth)
+			if !ok || auth.Key != "k#1" || auth.Header != "X-NenDB-Key" {
+				t.Errorf("Expected API key authenticator, got %#v", config.Authenticator)
+			}
+			if config.TLS != nil {
+				t.Errorf("Expected insecure: false alone not to create a TLS config, got %+v", config.TLS)
+			}
+
+			dev, err := LoadProfile(path, "dev")
+			if err != nil {
+				t.Fatalf("LoadProfile failed: %v", err)
+			}
+			if dev.BaseURL != "http://localhost:8080" || dev.Authenticator != nil || dev.TLS != nil {
+				t.Errorf("Unexpected dev config: %+v", dev)
+			}
+		})
+	}
+}
+
+func TestLoadProfileEnvironmentOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yamlProfiles), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NENDB_MAX_RETRIES", "1")
+
+	config, err := LoadProfile(path, "prod")
+	if err != nil {
+		t.Fatalf("LoadProfile failed: %v", err)
+	}
+	if config.MaxRetries != 1 {
+		t.Errorf("Expected NENDB_MAX_RETRIES to override profile, got %d", config.MaxRetries)
+	}
+}
+
+func TestLoadProfileErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := map[string]struct {
+		path    string
+		profile string
+	}{
+		"missing file":        {filepath.Join(dir, "missing.yaml"), "dev"},
+		"unknown extension":   {write("config.ini", "[dev]"), "dev"},
+		"unknown profile":     {write("a.yaml", yamlProfiles), "staging"},
+		"no default profile":  {write("b.yaml", yamlProfiles), ""},
+		"unknown setting":     {write("c.toml", "[dev]\nurll = \"x\"\n"), "dev"},
+		"invalid value":       {write("d.json", `{"dev": {"max_retries": "many"}}`), "dev"},
+		"setting outside":     {write("e.yaml", "url: http://x\n"), "dev"},
+		"unterminated string": {write("f.toml", "[dev]\nurl = \"http://x\n"), "dev"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadProfile(tt.path, tt.profile); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	tests := map[string]string{
+		"# full line":                    "",
+		"url: http://h/#x":               "url: http://h/#x",
+		"password: ab#cd # the password": "password: ab#cd ",
+		"password = ab#cd\t# tab":        "password = ab#cd\t",
+		`token: "a # b" # quoted`:        `token: "a # b" `,
+		"timeout: 10s #":                 "timeout: 10s ",
+	}
+	for line, want := range tests {
+		if got := stripComment(line); got != want {
+			t.Errorf("stripComment(%q) = %q, want %q", line, got, want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("default:\n  url: http://h/#x\n  password: ab#cd\n  username: alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadProfile(path, "")
+	if err != nil {
+		t.Fatalf("LoadProfile failed: %v", err)
+	}
+	auth, ok := config.Authenticator.(*BasicAuth)
+	if config.BaseURL != "http://h/#x" || !ok || auth.Password != "ab#cd" {
+		t.Errorf("Expected values containing # to be kept whole, got %q and %#v", config.BaseURL, config.Authenticator)
+	}
+}
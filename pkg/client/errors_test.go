@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func TestResponseErrorClassification(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	_, err := client.GetNode(ctx, 42)
+	if !stderrors.Is(err, errors.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	var respErr *errors.NenDBResponseError
+	if !stderrors.As(err, &respErr) {
+		t.Fatalf("Expected *NenDBResponseError, got %T", err)
+	}
+	if respErr.StatusCode != http.StatusNotFound || respErr.Code != "NOT_FOUND" {
+		t.Errorf("Expected 404 NOT_FOUND, got %d %q", respErr.StatusCode, respErr.Code)
+	}
+	if respErr.Method != "GET" || respErr.Endpoint != "/nodes/42" {
+		t.Errorf("Expected GET /nodes/42, got %s %s", respErr.Method, respErr.Endpoint)
+	}
+	if respErr.RequestID == "" {
+		t.Error("Expected request ID from response header")
+	}
+
+	srv.FailNext(1, http.StatusTooManyRequests)
+	if _, err := client.GetStatistics(ctx); !stderrors.Is(err, errors.ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+
+	srv.SetQueryHandler(func(query string, params map[string]interface{}) (interface{}, error) {
+		return nil, &nendbtest.StatusError{Status: http.StatusConflict, Code: "CONFLICT", Message: "locked"}
+	})
+	if _, err := client.Query(ctx, "MATCH (n) RETURN n", nil); !stderrors.Is(err, errors.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
+
+func TestTransportErrorUnwrapsToNetError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	client := newRetryClient(t, url, 0)
+	_, err := client.GetNode(context.Background(), 1)
+
+	var connErr *errors.NenDBConnectionError
+	if !stderrors.As(err, &connErr) {
+		t.Fatalf("Expected *NenDBConnectionError, got %T: %v", err, err)
+	}
+	var opErr *net.OpError
+	if !stderrors.As(err, &opErr) {
+		t.Errorf("Expected error chain to include *net.OpError, got %v", err)
+	}
+	if connErr.Method != "GET" || connErr.Endpoint != "/nodes/1" {
+		t.Errorf("Expected GET /nodes/1, got %s %s", connErr.Method, connErr.Endpoint)
+	}
+}
+
+func TestCancelledRequestUnwrapsToContextError(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.GetNode(ctx, 1)
+	if !stderrors.Is(err, context.Canceled) {
+		t.Errorf("Expected error chain to include context.Canceled, got %v", err)
+	}
+}
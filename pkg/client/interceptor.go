@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Request is a client call as seen by interceptors. Interceptors may change
+// any field before passing the request on.
+type Request struct {
+	// Operation is the client method that made the request, such as
+	// "GetNode" or "CreateNodes"
+	Operation string
+	// Method and Endpoint are the HTTP method and the path below the base URL
+	Method   string
+	Endpoint string
+	// Params are the query parameters
+	Params map[string]string
+	// Payload is the request body before JSON encoding, nil when there is none
+	Payload interface{}
+	// Header is sent with every attempt. It already holds the headers set by
+	// call options; credentials from ClientConfig.Authenticator are added
+	// later.
+	Header http.Header
+}
+
+// Response is the server's successful answer to a Request
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw JSON response body
+	Body []byte
+	// Attempts is the number of HTTP attempts made, including retries. It is
+	// zero for responses produced by an interceptor.
+	Attempts int
+}
+
+// RoundTrip performs a request. Exactly one of the response and the error is
+// non-nil; errors are the pkg/errors types the client methods return, so a
+// failed response is a *errors.NenDBResponseError carrying its status code.
+type RoundTrip func(ctx context.Context, req *Request) (*Response, error)
+
+// Interceptor wraps the RoundTrip that sends a request. It can inspect or
+// change the request, answer it without calling next, or inspect and replace
+// the response or error.
+type Interceptor func(next RoundTrip) RoundTrip
+
+// interceptors holds the chain registered with Use
+type interceptors struct {
+	mu    sync.Mutex
+	chain atomic.Pointer[[]Interceptor]
+}
+
+// Use adds interceptors around every request the client sends. The first
+// interceptor registered is the outermost. Interceptors run once per call,
+// outside the retry loop: the RoundTrip they wrap performs every attempt, so
+// an error it returns has already been retried. Use may be called while
+// requests are in flight; they keep the chain they started with.
+//
+//	c.Use(func(next client.RoundTrip) client.RoundTrip {
+//		return func(ctx context.Context, req *client.Request) (*client.Response, error) {
+//			start := time.Now()
+//			resp, err := next(ctx, req)
+//			log.Printf("%s %s took %v: %v", req.Operation, req.Endpoint, time.Since(start), err)
+//			return resp, err
+//		}
+//	})
+func (c *NenDBClient) Use(interceptors ...Interceptor) {
+	c.interceptors.mu.Lock()
+	defer c.interceptors.mu.Unlock()
+	var chain []Interceptor
+	if current := c.interceptors.chain.Load(); current != nil {
+		chain = append(chain, *current...)
+	}
+	chain = append(chain, interceptors...)
+	c.interceptors.chain.Store(&chain)
+}
+
+// wrap returns rt wrapped in the registered interceptors
+func (c *NenDBClient) wrap(rt RoundTrip) RoundTrip {
+	chain := c.interceptors.chain.Load()
+	if chain == nil {
+		return rt
+	}
+	for i := len(*chain) - 1; i >= 0; i-- {
+		rt = (*chain)[i](rt)
+	}
+	return rt
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func TestInterceptorsWrapRequests(t *testing.T) {
+	fake := nendbtest.NewServer()
+	defer fake.Close()
+	var tenant string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = r.Header.Get("X-Tenant")
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = srv.URL
+	config.RetryDelay = 0
+	config.SkipValidation = true
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var calls []string
+	var seen []*Request
+	var attempts []int
+	trace := func(name string) Interceptor {
+		return func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(ctx, req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+	client.Use(trace("outer"))
+	client.Use(trace("inner"), func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			req.Header.Set("X-Tenant", "acme")
+			seen = append(seen, req)
+			resp, err := next(ctx, req)
+			if resp != nil {
+				attempts = append(attempts, resp.Attempts)
+			}
+			return resp, err
+		}
+	})
+
+	ctx := context.Background()
+	if _, err := client.CreateNode(ctx, []string{"Person"}, map[string]interface{}{"name": "Alice"}); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if want := []string{"outer before", "inner before", "inner after", "outer after"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected interceptors to run in registration order, got %q", calls)
+	}
+	req := seen[0]
+	payload, _ := req.Payload.(map[string]interface{})
+	if req.Operation != "CreateNode" || req.Method != "POST" || req.Endpoint != "/nodes" || payload == nil || !reflect.DeepEqual(payload["labels"], []string{"Person"}) {
+		t.Errorf("Unexpected request %+v", req)
+	}
+	if tenant != "acme" {
+		t.Errorf("Expected the interceptor's header to be sent, got %q", tenant)
+	}
+
+	// Retries happen inside the RoundTrip the interceptors wrap
+	fake.FailNext(1, http.StatusServiceUnavailable)
+	if _, err := client.ListNodes(ctx, &NodeListOptions{Label: "Person"}); err != nil {
+		t.Fatalf("ListNodes failed: %v", err)
+	}
+	if len(seen) != 2 || seen[1].Operation != "ListNodes" || seen[1].Params["label"] != "Person" {
+		t.Errorf("Expected one interception per call, got %d", len(seen))
+	}
+	if !reflect.DeepEqual(attempts, []int{1, 2}) {
+		t.Errorf("Expected the response to report its attempts, got %v", attempts)
+	}
+}
+
+func TestInterceptorShortCircuits(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	before := srv.Requests()
+
+	injected := errors.NewConnectionError("injected fault", nil)
+	client.Use(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			switch req.Operation {
+			case "GetNode":
+				// Serve from a cache
+				return &Response{StatusCode: http.StatusOK, Body: []byte(`{"id": 7, "labels": ["Cached"]}`)}, nil
+			case "DeleteNode":
+				return nil, injected
+			}
+			return next(ctx, req)
+		}
+	})
+
+	node, err := client.GetNode(context.Background(), 7)
+	if err != nil || node.ID != 7 || node.Labels[0] != "Cached" {
+		t.Errorf("Expected the cached node, got %+v, %v", node, err)
+	}
+	if err := client.DeleteNode(context.Background(), 7); err != injected {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+	if srv.Requests() != before {
+		t.Errorf("Expected no requests to reach the server, got %d", srv.Requests()-before)
+	}
+}
+
+func TestInterceptorSeesParsedErrors(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	var status int
+	client.Use(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			var respErr *errors.NenDBResponseError
+			if stderrors.As(err, &respErr) {
+				status = respErr.StatusCode
+			}
+			if stderrors.Is(err, errors.ErrNotFound) && req.Method == http.MethodDelete {
+				// Treat deleting a missing node as success
+				return &Response{StatusCode: http.StatusNoContent}, nil
+			}
+			return resp, err
+		}
+	})
+
+	if err := client.DeleteNode(context.Background(), 42); err != nil {
+		t.Errorf("Expected the interceptor to absorb the 404, got %v", err)
+	}
+	if status != http.StatusNotFound {
+		t.Errorf("Expected the interceptor to see a 404 response error, got %d", status)
+	}
+
+	client.Use(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			return nil, nil
+		}
+	})
+	if _, err := client.GetStatistics(context.Background()); err == nil {
+		t.Error("Expected an error when an interceptor returns nothing")
+	}
+}
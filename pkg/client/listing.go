@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strconv"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// DefaultPageSize is the number of items requested per page when a list
+// call does not specify a limit
+const DefaultPageSize = 100
+
+// NodeListOptions filters and paginates ListNodes
+type NodeListOptions struct {
+	// Label restricts the listing to nodes carrying this label
+	Label string
+	// Limit is the maximum number of nodes per page
+	Limit int
+	// Cursor resumes a listing from the NextCursor of a previous page
+	Cursor string
+}
+
+// EdgeListOptions filters and paginates ListEdges
+type EdgeListOptions struct {
+	// Type restricts the listing to edges of this type
+	Type string
+	// Limit is the maximum number of edges per page
+	Limit int
+	// Cursor resumes a listing from the NextCursor of a previous page
+	Cursor string
+}
+
+// NodePage is a single page of a node listing
+type NodePage struct {
+	Nodes []types.GraphNode `json:"nodes"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// EdgePage is a single page of an edge listing
+type EdgePage struct {
+	Edges []types.GraphEdge `json:"edges"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// ListNodes retrieves a single page of nodes
+func (c *NenDBClient) ListNodes(ctx context.Context, opts *NodeListOptions, callOpts ...CallOption) (*NodePage, error) {
+	if opts == nil {
+		opts = &NodeListOptions{}
+	}
+	params := pageParams(opts.Limit, opts.Cursor)
+	if opts.Label != "" {
+		params["label"] = opts.Label
+	}
+
+	respBody, err := c.makeRequest(ctx, op("ListNodes"), "GET", "/nodes", nil, params, callOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var page NodePage
+	if err := json.Unmarshal(respBody, &page); err != nil {
+		return nil, errors.NewResponseError("Failed to parse node listing", map[string]interface{}{"error": err.Error()})
+	}
+
+	return &page, nil
+}
+
+// ListEdges retrieves a single page of edges
+func (c *NenDBClient) ListEdges(ctx context.Context, opts *EdgeListOptions, callOpts ...CallOption) (*EdgePage, error) {
+	if opts == nil {
+		opts = &EdgeListOptions{}
+	}
+	params := pageParams(opts.Limit, opts.Cursor)
+	if opts.Type != "" {
+		params["type"] = opts.Type
+	}
+
+	respBody, err := c.makeRequest(ctx, op("ListEdges"), "GET", "/edges", nil, params, callOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var page EdgePage
+	if err := json.Unmarshal(respBody, &page); err != nil {
+		return nil, errors.NewResponseError("Failed to parse edge listing", map[string]interface{}{"error": err.Error()})
+	}
+
+	return &page, nil
+}
+
+// Nodes returns an iterator over every node matching opts. Pages are fetched
+// lazily as the iteration advances; opts.Limit sets the page size. A failed
+// page fetch is yielded as an error and ends the iteration.
+//
+//	for node, err := range client.Nodes(ctx, &client.NodeListOptions{Label: "Person"}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(node.ID)
+//	}
+func (c *NenDBClient) Nodes(ctx context.Context, opts *NodeListOptions, callOpts ...CallOption) iter.Seq2[*types.GraphNode, error] {
+	return func(yield func(*types.GraphNode, error) bool) {
+		pageOpts := NodeListOptions{}
+		if opts != nil {
+			pageOpts = *opts
+		}
+		for {
+			page, err := c.ListNodes(ctx, &pageOpts, callOpts...)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range page.Nodes {
+				if !yield(&page.Nodes[i], nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			pageOpts.Cursor = page.NextCursor
+		}
+	}
+}
+
+// Edges returns an iterator over every edge matching opts. It behaves like
+// Nodes.
+func (c *NenDBClient) Edges(ctx context.Context, opts *EdgeListOptions, callOpts ...CallOption) iter.Seq2[*types.GraphEdge, error] {
+	return func(yield func(*types.GraphEdge, error) bool) {
+		pageOpts := EdgeListOptions{}
+		if opts != nil {
+			pageOpts = *opts
+		}
+		for {
+			page, err := c.ListEdges(ctx, &pageOpts, callOpts...)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range page.Edges {
+				if !yield(&page.Edges[i], nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			pageOpts.Cursor = page.NextCursor
+		}
+	}
+}
+
+// pageParams builds the query parameters shared by the list endpoints
+func pageParams(limit int, cursor string) map[string]string {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	params := map[string]string{"limit": strconv.Itoa(limit)}
+	if cursor != "" {
+		params["cursor"] = cursor
+	}
+	return params
+}
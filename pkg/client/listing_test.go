@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func TestListNodesPagination(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		srv.AddNode([]string{"Person"}, nil)
+		srv.AddNode([]string{"Company"}, nil)
+	}
+
+	page, err := client.ListNodes(ctx, &NodeListOptions{Label: "Person", Limit: 3})
+	if err != nil {
+		t.Fatalf("Failed to list nodes: %v", err)
+	}
+	if len(page.Nodes) != 3 {
+		t.Errorf("Expected 3 nodes on first page, got %d", len(page.Nodes))
+	}
+	if page.NextCursor == "" {
+		t.Fatal("Expected a next cursor on first page")
+	}
+
+	page, err = client.ListNodes(ctx, &NodeListOptions{Label: "Person", Limit: 3, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("Failed to list nodes: %v", err)
+	}
+	if len(page.Nodes) != 2 {
+		t.Errorf("Expected 2 nodes on last page, got %d", len(page.Nodes))
+	}
+	if page.NextCursor != "" {
+		t.Errorf("Expected no cursor on last page, got %q", page.NextCursor)
+	}
+	for _, n := range page.Nodes {
+		if n.Labels[0] != "Person" {
+			t.Errorf("Expected only Person nodes, got %v", n.Labels)
+		}
+	}
+}
+
+func TestNodesIterator(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		srv.AddNode(nil, nil)
+	}
+
+	var ids []int
+	for node, err := range client.Nodes(ctx, &NodeListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("Unexpected iteration error: %v", err)
+		}
+		ids = append(ids, node.ID)
+	}
+	if len(ids) != 7 {
+		t.Fatalf("Expected 7 nodes, got %d", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Errorf("Expected ascending IDs, got %v", ids)
+		}
+	}
+
+	// Breaking early stops the iteration
+	count := 0
+	for range client.Nodes(ctx, &NodeListOptions{Limit: 2}) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("Expected iteration to stop after 3 nodes, got %d", count)
+	}
+}
+
+func TestEdgesIterator(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	a := srv.AddNode(nil, nil)
+	b := srv.AddNode(nil, nil)
+	for i := 0; i < 3; i++ {
+		srv.AddEdge(a.ID, b.ID, "KNOWS", nil)
+		srv.AddEdge(b.ID, a.ID, "FOLLOWS", nil)
+	}
+
+	count := 0
+	for edge, err := range client.Edges(context.Background(), &EdgeListOptions{Type: "FOLLOWS", Limit: 1}) {
+		if err != nil {
+			t.Fatalf("Unexpected iteration error: %v", err)
+		}
+		if edge.Type != "FOLLOWS" {
+			t.Errorf("Expected FOLLOWS edge, got %s", edge.Type)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 edges, got %d", count)
+	}
+}
+
+func TestNodesIteratorYieldsErrors(t *testing.T) {
+	srv := nendbtest.NewServer()
+	client := newTestClient(t, srv)
+	srv.Close()
+
+	for node, err := range client.Nodes(context.Background(), nil) {
+		if err == nil {
+			t.Errorf("Expected error from closed server, got node %v", node)
+		}
+	}
+}
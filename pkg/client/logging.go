@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+)
+
+// redacted replaces values that must not appear in logs
+const redacted = "[REDACTED]"
+
+// redactedFields are payload fields holding user data; their values are
+// replaced while their keys are kept
+var redactedFields = map[string]bool{"properties": true, "params": true}
+
+// callLog logs a request through its attempts. Its methods do nothing on a
+// nil *callLog.
+//
+// Levels: the request with its redacted headers and payload and each attempt
+// are logged at debug, completed requests at info, retries and client errors
+// (4xx) at warn, and other failures at error.
+type callLog struct {
+	logger   *slog.Logger
+	attrs    []slog.Attr
+	start    time.Time
+	attempts int
+}
+
+// startLog begins logging req; it returns nil when logger is nil
+func startLog(ctx context.Context, logger *slog.Logger, req *Request) *callLog {
+	if logger == nil {
+		return nil
+	}
+	l := &callLog{
+		logger: logger,
+		start:  time.Now(),
+		attrs: []slog.Attr{
+			slog.String("operation", req.Operation),
+			slog.String("method", req.Method),
+			slog.String("endpoint", req.Endpoint),
+		},
+	}
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		attrs := []slog.Attr{slog.Any("headers", redactHeaders(req.Header))}
+		if len(req.Params) > 0 {
+			attrs = append(attrs, slog.Any("params", req.Params))
+		}
+		if req.Payload != nil {
+			attrs = append(attrs, slog.Any("payload", redactPayload(req.Payload)))
+		}
+		l.log(ctx, slog.LevelDebug, "nendb request", attrs...)
+	}
+	return l
+}
+
+// attempt logs the outcome of one attempt
+func (l *callLog) attempt(ctx context.Context, attempt int, resp *http.Response, err error, d time.Duration) {
+	if l == nil {
+		return
+	}
+	l.attempts = attempt
+	attrs := []slog.Attr{slog.Int("attempt", attempt), slog.Duration("duration", d)}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.log(ctx, slog.LevelDebug, "nendb attempt", attrs...)
+}
+
+// retry logs that another attempt follows after delay
+func (l *callLog) retry(ctx context.Context, attempt int, delay time.Duration, err error) {
+	if l == nil {
+		return
+	}
+	l.log(ctx, slog.LevelWarn, "nendb retrying request",
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.String("error", err.Error()))
+}
+
+// end logs the request's outcome
+func (l *callLog) end(ctx context.Context, resp *Response, err error) {
+	if l == nil {
+		return
+	}
+	duration := slog.Duration("duration", time.Since(l.start))
+	if err == nil {
+		l.log(ctx, slog.LevelInfo, "nendb request completed",
+			slog.Int("status", resp.StatusCode),
+			slog.Int("attempts", resp.Attempts),
+			duration)
+		return
+	}
+
+	level := slog.LevelError
+	attrs := []slog.Attr{
+		slog.String("error", err.Error()),
+		slog.String("error_type", errors.TypeName(err)),
+		slog.Int("attempts", l.attempts),
+		duration,
+	}
+	var respErr *errors.NenDBResponseError
+	if stderrors.As(err, &respErr) && respErr.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", respErr.StatusCode))
+		if respErr.StatusCode < 500 {
+			level = slog.LevelWarn
+		}
+	}
+	l.log(ctx, level, "nendb request failed", attrs...)
+}
+
+func (l *callLog) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	l.logger.LogAttrs(ctx, level, msg, append(l.attrs[:len(l.attrs):len(l.attrs)], attrs...)...)
+}
+
+// redactHeaders returns a copy of h with credentials replaced
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for key := range out {
+		if sensitiveHeader(key) {
+			out[key] = []string{redacted}
+		}
+	}
+	return out
+}
+
+// sensitiveHeader reports whether the header named key may carry credentials
+func sensitiveHeader(key string) bool {
+	key = strings.ToLower(key)
+	switch key {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	for _, s := range []string{"api-key", "apikey", "token", "secret", "password"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactPayload returns the JSON form of payload with property and query
+// parameter values replaced
+func redactPayload(payload interface{}) interface{} {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return redacted
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return redacted
+	}
+	return redactValue(generic)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if fields, ok := child.(map[string]interface{}); ok && redactedFields[key] {
+				for field := range fields {
+					fields[field] = redacted
+				}
+				continue
+			}
+			val[key] = redactValue(child)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactValue(item)
+		}
+	}
+	return v
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func TestLogging(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	config := DefaultConfig()
+	config.BaseURL = srv.URL
+	config.RetryDelay = 0
+	config.SkipValidation = true
+	config.Authenticator = &APIKeyAuth{Key: "key-secret"}
+	config.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	node, err := client.CreateNode(ctx, []string{"Person"}, map[string]interface{}{"ssn": "123-45-6789"},
+		WithHeader("X-Session-Token", "token-secret"), WithHeader("X-Request-Source", "test"))
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	srv.FailNext(1, http.StatusServiceUnavailable)
+	if _, err := client.GetNode(ctx, node.ID); err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if _, err := client.GetNode(ctx, 999); err == nil {
+		t.Fatal("Expected GetNode to fail")
+	}
+
+	for _, secret := range []string{"123-45-6789", "key-secret", "token-secret"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("Expected %q to be redacted from the logs:\n%s", secret, buf.String())
+		}
+	}
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	find := func(msg, endpoint string) map[string]interface{} {
+		for _, r := range records {
+			if r["msg"] == msg && r["endpoint"] == endpoint {
+				return r
+			}
+		}
+		t.Fatalf("No %q record for %s in:\n%s", msg, endpoint, buf.String())
+		return nil
+	}
+
+	request := find("nendb request", "/nodes")
+	payload := request["payload"].(map[string]interface{})
+	headers := request["headers"].(map[string]interface{})
+	if request["level"] != "DEBUG" || request["operation"] != "CreateNode" || payload["properties"].(map[string]interface{})["ssn"] != redacted {
+		t.Errorf("Unexpected request record %v", request)
+	}
+	if headers["X-Session-Token"].([]interface{})[0] != redacted || headers["X-Request-Source"].([]interface{})[0] != "test" {
+		t.Errorf("Expected only credential headers to be redacted, got %v", headers)
+	}
+
+	retry := find("nendb retrying request", "/nodes/1")
+	if retry["level"] != "WARN" || retry["attempt"] != 1.0 || retry["delay"] == nil {
+		t.Errorf("Unexpected retry record %v", retry)
+	}
+	done := find("nendb request completed", "/nodes/1")
+	if done["level"] != "INFO" || done["status"] != 200.0 || done["attempts"] != 2.0 || done["duration"] == nil {
+		t.Errorf("Unexpected completion record %v", done)
+	}
+	failed := find("nendb request failed", "/nodes/999")
+	if failed["level"] != "WARN" || failed["status"] != 404.0 || failed["error_type"] != "NenDBResponseError" {
+		t.Errorf("Unexpected failure record %v", failed)
+	}
+}
+
+func TestLoggingLevels(t *testing.T) {
+	var buf bytes.Buffer
+	config := DefaultConfig()
+	config.BaseURL = "http://127.0.0.1:1"
+	config.MaxRetries = 0
+	config.SkipValidation = true
+	config.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.GetStatistics(context.Background()); err == nil {
+		t.Fatal("Expected the request to fail")
+	}
+	out := buf.String()
+	if !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "error_type=NenDBConnectionError") {
+		t.Errorf("Expected a connection failure at error level, got %q", out)
+	}
+	if strings.Contains(out, "nendb attempt") || strings.Contains(out, `msg="nendb request"`) {
+		t.Errorf("Expected debug records to be filtered out, got %q", out)
+	}
+}
+
+func TestRedactPayload(t *testing.T) {
+	payload := map[string]interface{}{
+		"query":  "MATCH (n) WHERE n.name = $name RETURN n",
+		"params": map[string]interface{}{"name": "Alice"},
+		"nodes": []interface{}{
+			map[string]interface{}{"labels": []string{"A"}, "properties": map[string]interface{}{"age": 30}},
+		},
+	}
+	got, _ := json.Marshal(redactPayload(payload))
+	want := `{"nodes":[{"labels":["A"],"properties":{"age":"[REDACTED]"}}],"params":{"name":"[REDACTED]"},"query":"MATCH (n) WHERE n.name = $name RETURN n"}`
+	if string(got) != want {
+		t.Errorf("redactPayload = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Headers set by call options
+const (
+	// IdempotencyKeyHeader lets the server recognise a repeated write
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// ConsistencyHeader selects the consistency level of a read
+	ConsistencyHeader = "X-NenDB-Consistency"
+)
+
+// Consistency is the consistency level requested for a call
+type Consistency string
+
+// Consistency levels understood by the server
+const (
+	// ConsistencyStrong reads the latest committed state
+	ConsistencyStrong Consistency = "strong"
+	// ConsistencyEventual may read from a replica that lags behind
+	ConsistencyEventual Consistency = "eventual"
+)
+
+// CallOption customises a single client call. Options are applied in order,
+// so a later option overrides an earlier one.
+type CallOption func(*callOptions)
+
+// callOptions holds the settings collected from a call's options
+type callOptions struct {
+	timeout        time.Duration
+	header         http.Header
+	idempotencyKey string
+	retries        int
+	retriesSet     bool
+	consistency    Consistency
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithTimeout bounds each request made by the call, including its retries
+// and the delays between them. ClientConfig.Timeout still limits every
+// attempt. Non-positive durations are ignored.
+func WithTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithHeader adds a header to the call's requests, for example a tracing
+// header. It may be given several times, and overrides default headers with
+// the same name. Authentication headers are set after it.
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithIdempotencyKey sends key in the Idempotency-Key header of the call's
+// POST requests so the server applies a repeated request only once. Such
+// requests are retried like idempotent ones, which makes a CreateNode safe
+// to retry after a timeout or a 500 response. Batch calls send key with the
+// first chunk and key-1, key-2, ... with the following ones.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithRetries sets the number of retries allowed after the first attempt;
+// zero disables retries. With the default ExponentialBackoff policy it
+// replaces MaxRetries; a custom RetryPolicy is capped at n retries.
+func WithRetries(n int) CallOption {
+	return func(o *callOptions) {
+		o.retries = max(n, 0)
+		o.retriesSet = true
+	}
+}
+
+// WithConsistency requests a consistency level for the call's reads
+func WithConsistency(level Consistency) CallOption {
+	return func(o *callOptions) {
+		o.consistency = level
+	}
+}
+
+// NewIdempotencyKey returns a random key for WithIdempotencyKey. Reuse the
+// same key when repeating a call by hand. It panics if the system's random
+// source fails, as a predictable key could match another caller's.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("nendb: failed to generate idempotency key: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// context applies the call's timeout to ctx
+func (o *callOptions) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
+
+// headers returns the headers set by the call's options for a request
+// with method
+func (o *callOptions) headers(method string) http.Header {
+	h := o.header.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	if o.consistency != "" {
+		h.Set(ConsistencyHeader, string(o.consistency))
+	}
+	if o.idempotencyKey != "" && !isIdempotent(method) {
+		h.Set(IdempotencyKeyHeader, o.idempotencyKey)
+	}
+	return h
+}
+
+// retryMethod is the method the retry policy sees for req. A request with an
+// idempotency key is as safe to repeat as a PUT.
+func retryMethod(req *http.Request) string {
+	if req.Header.Get(IdempotencyKeyHeader) != "" && !isIdempotent(req.Method) {
+		return http.MethodPut
+	}
+	return req.Method
+}
+
+// retryPolicy returns policy limited to the call's retries
+func (o *callOptions) retryPolicy(policy RetryPolicy) RetryPolicy {
+	if !o.retriesSet {
+		return policy
+	}
+	if b, ok := policy.(*ExponentialBackoff); ok {
+		limited := *b
+		limited.MaxRetries = o.retries
+		return &limited
+	}
+	return retryLimit{policy: policy, max: o.retries}
+}
+
+// retryLimit caps the retries of another policy
+type retryLimit struct {
+	policy RetryPolicy
+	max    int
+}
+
+// NextRetry implements RetryPolicy
+func (l retryLimit) NextRetry(attempt int, method string, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt > l.max {
+		return 0, false
+	}
+	return l.policy.NextRetry(attempt, method, resp, err)
+}
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// recordingServer serves a fake NenDB and records the headers of every
+// request it receives
+func recordingServer(t *testing.T) (*NenDBClient, func() []http.Header) {
+	t.Helper()
+	fake := nendbtest.NewServer()
+	t.Cleanup(fake.Close)
+
+	var mu sync.Mutex
+	var headers []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	config := DefaultConfig()
+	config.BaseURL = srv.URL
+	config.MaxRetries = 0
+	config.SkipValidation = true
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client, func() []http.Header {
+		mu.Lock()
+		defer mu.Unlock()
+		return headers
+	}
+}
+
+func TestCallOptionHeaders(t *testing.T) {
+	client, headers := recordingServer(t)
+	ctx := context.Background()
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	node, err := client.CreateNode(ctx, []string{"Person"}, nil,
+		WithHeader("traceparent", traceparent),
+		WithHeader("User-Agent", "importer/1.0"),
+		WithIdempotencyKey("create-alice"))
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if _, err := client.GetNode(ctx, node.ID, WithConsistency(ConsistencyStrong), WithIdempotencyKey("ignored")); err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+
+	got := headers()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(got))
+	}
+	create, get := got[0], got[1]
+	if create.Get("traceparent") != traceparent || create.Get("User-Agent") != "importer/1.0" || create.Get(IdempotencyKeyHeader) != "create-alice" {
+		t.Errorf("Unexpected create headers %v", create)
+	}
+	if create.Get(ConsistencyHeader) != "" {
+		t.Errorf("Expected no consistency header without WithConsistency, got %q", create.Get(ConsistencyHeader))
+	}
+	if get.Get(ConsistencyHeader) != "strong" || get.Get(IdempotencyKeyHeader) != "" || get.Get("traceparent") != "" {
+		t.Errorf("Unexpected get headers %v", get)
+	}
+}
+
+func TestIdempotencyKeyMakesPostRetryable(t *testing.T) {
+	srv, calls := statusServer(t, nil, http.StatusInternalServerError)
+	client := newRetryClient(t, srv.URL, 3)
+	if _, err := client.CreateNode(context.Background(), nil, nil); err == nil || atomic.LoadInt32(calls) != 1 {
+		t.Fatalf("Expected a failed POST without retries, got %v after %d calls", err, atomic.LoadInt32(calls))
+	}
+
+	srv, calls = statusServer(t, nil, http.StatusInternalServerError)
+	client = newRetryClient(t, srv.URL, 3)
+	if _, err := client.CreateNode(context.Background(), nil, nil, WithIdempotencyKey(NewIdempotencyKey())); err != nil {
+		t.Fatalf("Expected the keyed POST to be retried, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+
+	if a, b := NewIdempotencyKey(), NewIdempotencyKey(); len(a) != 32 || a == b {
+		t.Errorf("Expected distinct random keys, got %q and %q", a, b)
+	}
+}
+
+func TestWithRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []CallOption
+		wantErr bool
+		calls   int32
+	}{
+		{"default policy", nil, true, 4},
+		{"disabled", []CallOption{WithRetries(0)}, true, 1},
+		{"raised", []CallOption{WithRetries(6)}, false, 6},
+		{"last option wins", []CallOption{WithRetries(6), WithRetries(1)}, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := statusServer(t, nil, 503, 503, 503, 503, 503)
+			client := newRetryClient(t, srv.URL, 3)
+			_, err := client.GetStatistics(context.Background(), tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.calls {
+				t.Errorf("Expected %d attempts, got %d", tt.calls, got)
+			}
+		})
+	}
+
+	// Custom policies can only be capped
+	srv, calls := statusServer(t, nil, 503, 503, 503)
+	client := newRetryClient(t, srv.URL, 0)
+	client.retryPolicy = retryFunc(func(attempt int, method string, resp *http.Response, err error) (time.Duration, bool) {
+		return time.Millisecond, true
+	})
+	if _, err := client.GetStatistics(context.Background(), WithRetries(1)); err == nil || atomic.LoadInt32(calls) != 2 {
+		t.Errorf("Expected the custom policy to stop after 1 retry, got %v after %d calls", err, atomic.LoadInt32(calls))
+	}
+}
+
+// retryFunc adapts a function to RetryPolicy
+type retryFunc func(attempt int, method string, resp *http.Response, err error) (time.Duration, bool)
+
+func (f retryFunc) NextRetry(attempt int, method string, resp *http.Response, err error) (time.Duration, bool) {
+	return f(attempt, method, resp, err)
+}
+
+func TestWithTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	client := newRetryClient(t, srv.URL, 3)
+
+	start := time.Now()
+	_, err := client.GetStatistics(context.Background(), WithTimeout(20*time.Millisecond))
+	var timeoutErr *errors.NenDBTimeoutError
+	if !stderrors.As(err, &timeoutErr) || !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the call to give up after its timeout, took %v", elapsed)
+	}
+}
+
+func TestBatchIdempotencyKeysPerChunk(t *testing.T) {
+	client, headers := recordingServer(t)
+	client.config.BatchSize = 2
+
+	nodes := make([]types.GraphNode, 5)
+	if _, err := client.CreateNodes(context.Background(), nodes, WithIdempotencyKey("load")); err != nil {
+		t.Fatalf("CreateNodes failed: %v", err)
+	}
+	var keys []string
+	for _, h := range headers() {
+		keys = append(keys, h.Get(IdempotencyKeyHeader))
+	}
+	if len(keys) != 3 || keys[0] != "load" || keys[1] != "load-1" || keys[2] != "load-2" {
+		t.Errorf("Expected a key per chunk, got %q", keys)
+	}
+}
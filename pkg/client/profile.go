@@ -0,0 +1,182 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+)
+
+// profiles maps a profile name to its settings
+type profiles map[string]map[string]string
+
+// readProfiles parses a profile file, choosing the format by extension. The
+// YAML and TOML readers accept the flat two-level layout profiles use, not
+// the full languages.
+func readProfiles(path string) (profiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewValidationError("Failed to read profile file", map[string]interface{}{"file": path, "error": err.Error()})
+	}
+
+	var parsed profiles
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		parsed, err = parseYAMLProfiles(data)
+	case ".toml":
+		parsed, err = parseTOMLProfiles(data)
+	case ".json":
+		parsed, err = parseJSONProfiles(data)
+	default:
+		return nil, errors.NewValidationError("Unsupported profile file format", map[string]interface{}{"file": path, "extension": ext})
+	}
+	if err != nil {
+		return nil, errors.NewValidationError("Failed to parse profile file", map[string]interface{}{"file": path, "error": err.Error()})
+	}
+	return parsed, nil
+}
+
+// parseYAMLProfiles reads profile names at column zero followed by indented
+// "key: value" lines
+func parseYAMLProfiles(data []byte) (profiles, error) {
+	result := make(profiles)
+	var current map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		raw := strings.TrimRight(stripComment(scanner.Text()), " \t")
+		text := strings.TrimSpace(raw)
+		if text == "" || text == "---" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if raw[0] != ' ' && raw[0] != '\t' {
+			if value != "" {
+				return nil, fmt.Errorf("line %d: top-level keys must name a profile", line)
+			}
+			current = make(map[string]string)
+			result[key] = current
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a profile", line)
+		}
+		unquoted, err := unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		current[key] = unquoted
+	}
+	return result, scanner.Err()
+}
+
+// parseTOMLProfiles reads [profile] tables of "key = value" lines
+func parseTOMLProfiles(data []byte) (profiles, error) {
+	result := make(profiles)
+	var current map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", line)
+			}
+			name, err := unquote(strings.TrimSpace(text[1 : len(text)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			current = make(map[string]string)
+			result[name] = current
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", line)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a profile table", line)
+		}
+		unquoted, err := unquote(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		current[strings.TrimSpace(key)] = unquoted
+	}
+	return result, scanner.Err()
+}
+
+// parseJSONProfiles reads an object of profile objects. Numbers and booleans
+// are kept in their JSON spelling.
+func parseJSONProfiles(data []byte) (profiles, error) {
+	var raw map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	result := make(profiles, len(raw))
+	for name, settings := range raw {
+		result[name] = make(map[string]string, len(settings))
+		for key, value := range settings {
+			var s string
+			if json.Unmarshal(value, &s) != nil {
+				s = string(value)
+			}
+			result[name][key] = s
+		}
+	}
+	return result, nil
+}
+
+// stripComment removes a trailing # comment that is not inside quotes. As in
+// YAML and TOML, a # starts a comment only at the start of the line or after
+// whitespace, so values such as http://h/#x are kept whole.
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote strips matching double or single quotes from a scalar value
+func unquote(value string) (string, error) {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '"' && value[len(value)-1] == '"':
+			return strconv.Unquote(value)
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return value[1 : len(value)-1], nil
+		}
+	}
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		return "", fmt.Errorf("unterminated string %s", value)
+	}
+	return value, nil
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the budget of one class of requests
+type RateLimit struct {
+	// Rate is the sustained number of requests per second; zero means no
+	// rate limit
+	Rate float64
+	// Burst is the number of requests that may be sent at once after a quiet
+	// period. Zero means Rate rounded up, and at least 1.
+	Burst int
+	// MaxInFlight limits concurrent requests of the class; zero means no
+	// limit
+	MaxInFlight int
+}
+
+// RateLimits throttles the client so that fan-out does not overwhelm the
+// server. Every attempt, retries included, takes a token from the bucket of
+// its class and holds an in-flight slot while it waits for the response.
+// Callers block until the request may proceed or their context is done.
+type RateLimits struct {
+	// Reads covers GET requests
+	Reads RateLimit
+	// Writes covers requests that create, update or delete data
+	Writes RateLimit
+	// Algorithms covers RunBFS, RunDijkstra, RunPageRank and Query, which are
+	// the most expensive calls for the server
+	Algorithms RateLimit
+	// MaxInFlight limits concurrent requests of all classes together; zero
+	// means no limit
+	MaxInFlight int
+}
+
+// requestClass is the budget a request draws from
+type requestClass int
+
+const (
+	classRead requestClass = iota
+	classWrite
+	classAlgorithm
+)
+
+// classify returns the budget req draws from
+func classify(req *Request) requestClass {
+	switch {
+	case strings.HasPrefix(req.Endpoint, "/algorithms/") || req.Endpoint == "/query":
+		return classAlgorithm
+	case req.Method == http.MethodGet || req.Method == http.MethodHead:
+		return classRead
+	}
+	return classWrite
+}
+
+// limiter enforces RateLimits. A nil *limiter admits every request.
+type limiter struct {
+	classes [3]classLimiter
+	global  semaphore
+}
+
+// classLimiter enforces a single RateLimit; nil fields are unlimited
+type classLimiter struct {
+	bucket *tokenBucket
+	sem    semaphore
+}
+
+func newLimiter(limits *RateLimits) *limiter {
+	if limits == nil {
+		return nil
+	}
+	l := &limiter{global: newSemaphore(limits.MaxInFlight)}
+	for class, limit := range []RateLimit{limits.Reads, limits.Writes, limits.Algorithms} {
+		l.classes[class] = classLimiter{
+			bucket: newTokenBucket(limit.Rate, limit.Burst),
+			sem:    newSemaphore(limit.MaxInFlight),
+		}
+	}
+	return l
+}
+
+// acquire waits until a request of class may be sent. The returned function
+// releases its in-flight slots and must be called once the response has been
+// read.
+func (l *limiter) acquire(ctx context.Context, class requestClass) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	cl := &l.classes[class]
+	if err := cl.bucket.wait(ctx); err != nil {
+		return nil, err
+	}
+	if err := cl.sem.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := l.global.acquire(ctx); err != nil {
+		cl.sem.release()
+		return nil, err
+	}
+	return func() {
+		l.global.release()
+		cl.sem.release()
+	}, nil
+}
+
+// tokenBucket holds up to burst tokens and refills at rate tokens per
+// second. A nil *tokenBucket never blocks.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = max(int(math.Ceil(rate)), 1)
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// using it. Tokens may go negative; later callers queue behind earlier ones.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// unreserve returns a token taken by a caller that gave up waiting
+func (b *tokenBucket) unreserve() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// wait blocks until a token is available or ctx is done. It fails at once
+// when ctx's deadline would pass before the token becomes available.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	now := time.Now()
+	delay := b.reserve(now)
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		b.unreserve()
+		return context.DeadlineExceeded
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		b.unreserve()
+		return err
+	}
+	return nil
+}
+
+// semaphore limits concurrency; a nil semaphore never blocks
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func newLimitedClient(t *testing.T, url string, limits *RateLimits) *NenDBClient {
+	t.Helper()
+	config := DefaultConfig()
+	config.BaseURL = url
+	config.MaxRetries = 0
+	config.SkipValidation = true
+	config.RateLimits = limits
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestRateLimitBudgetsAreSeparate(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newLimitedClient(t, srv.URL, &RateLimits{Writes: RateLimit{Rate: 50, Burst: 1}})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := client.CreateNode(ctx, nil, nil); err != nil {
+			t.Fatalf("CreateNode failed: %v", err)
+		}
+	}
+	// The burst covers the first write; the other five wait 20ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected writes to be throttled, 6 took %v", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 20; i++ {
+		if _, err := client.GetNode(ctx, 1); err != nil {
+			t.Fatalf("GetNode failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 80*time.Millisecond {
+		t.Errorf("Expected reads not to draw from the write budget, 20 took %v", elapsed)
+	}
+}
+
+func TestRateLimitHonoursContext(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newLimitedClient(t, srv.URL, &RateLimits{Algorithms: RateLimit{Rate: 1}})
+
+	if _, err := client.RunPageRank(context.Background(), 10, 0.01); err != nil {
+		t.Fatalf("RunPageRank failed: %v", err)
+	}
+	before := srv.Requests()
+
+	// The next token is a second away, beyond the deadline, so the call
+	// fails without waiting
+	start := time.Now()
+	_, err := client.RunPageRank(context.Background(), 10, 0.01, WithTimeout(100*time.Millisecond))
+	var timeoutErr *errors.NenDBTimeoutError
+	if !stderrors.As(err, &timeoutErr) || !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected to fail fast, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := client.Query(ctx, "MATCH (n) RETURN n", nil); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation while waiting, got %v", err)
+	}
+	if srv.Requests() != before {
+		t.Errorf("Expected throttled calls not to reach the server")
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var mu sync.Mutex
+	var active, peak, algorithms int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		if r.URL.Path == "/algorithms/bfs" {
+			algorithms++
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		active--
+		if r.URL.Path == "/algorithms/bfs" {
+			algorithms--
+		}
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	client := newLimitedClient(t, srv.URL, &RateLimits{
+		MaxInFlight: 3,
+		Algorithms:  RateLimit{MaxInFlight: 1},
+	})
+
+	var wg sync.WaitGroup
+	var algoPeak int
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if i%2 == 0 {
+				_, err = client.RunBFS(context.Background(), 1, 2, 3)
+				mu.Lock()
+				algoPeak = max(algoPeak, algorithms)
+				mu.Unlock()
+			} else {
+				_, err = client.GetStatistics(context.Background())
+			}
+			if err != nil {
+				t.Errorf("Request failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 3 || algoPeak > 1 {
+		t.Errorf("Expected at most 3 requests and 1 algorithm in flight, got %d and %d", peak, algoPeak)
+	}
+
+	// A caller waiting for a slot gives up with its context
+	block := make(chan struct{})
+	blocking := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		w.Write([]byte(`{}`))
+	}))
+	defer blocking.Close()
+	defer close(block)
+	client = newLimitedClient(t, blocking.URL, &RateLimits{MaxInFlight: 1})
+	go client.GetStatistics(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	if _, err := client.GetStatistics(context.Background(), WithTimeout(20*time.Millisecond)); !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout while waiting for a slot, got %v", err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		req  Request
+		want requestClass
+	}{
+		{Request{Method: "GET", Endpoint: "/nodes/1"}, classRead},
+		{Request{Method: "POST", Endpoint: "/nodes/batch"}, classWrite},
+		{Request{Method: "DELETE", Endpoint: "/edges/3"}, classWrite},
+		{Request{Method: "POST", Endpoint: "/algorithms/pagerank"}, classAlgorithm},
+		{Request{Method: "POST", Endpoint: "/query"}, classAlgorithm},
+	}
+	for _, tt := range tests {
+		if got := classify(&tt.req); got != tt.want {
+			t.Errorf("classify(%s %s) = %d, want %d", tt.req.Method, tt.req.Endpoint, got, tt.want)
+		}
+	}
+}
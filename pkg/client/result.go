@@ -0,0 +1,254 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// Result holds the rows returned by a query. Row values are kept as raw JSON
+// and decoded on demand, so no precision is lost before the caller picks a
+// target type.
+type Result struct {
+	Columns []string            `json:"columns"`
+	Rows    [][]json.RawMessage `json:"rows"`
+	Summary ResultSummary       `json:"summary"`
+}
+
+// ResultSummary describes how a query was executed
+type ResultSummary struct {
+	// Query is the query text that produced the result
+	Query string `json:"query,omitempty"`
+	// ServerTime is the execution time reported by the server
+	ServerTime time.Duration `json:"server_time"`
+	// RoundTrip is the time from sending the request to parsing the response
+	RoundTrip time.Duration `json:"round_trip"`
+
+	NodesCreated  int `json:"nodes_created"`
+	NodesDeleted  int `json:"nodes_deleted"`
+	EdgesCreated  int `json:"edges_created"`
+	EdgesDeleted  int `json:"edges_deleted"`
+	PropertiesSet int `json:"properties_set"`
+}
+
+// queryResponse is the wire format of the /query endpoint
+type queryResponse struct {
+	Columns []string            `json:"columns"`
+	Rows    [][]json.RawMessage `json:"rows"`
+	Summary struct {
+		ExecutionTimeMS float64 `json:"execution_time_ms"`
+		NodesCreated    int     `json:"nodes_created"`
+		NodesDeleted    int     `json:"nodes_deleted"`
+		EdgesCreated    int     `json:"edges_created"`
+		EdgesDeleted    int     `json:"edges_deleted"`
+		PropertiesSet   int     `json:"properties_set"`
+	} `json:"summary"`
+}
+
+// parseResult converts a /query response body into a Result
+func parseResult(query string, body []byte, roundTrip time.Duration) (*Result, error) {
+	var resp queryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Columns == nil {
+		resp.Columns = []string{}
+	}
+	if resp.Rows == nil {
+		resp.Rows = [][]json.RawMessage{}
+	}
+
+	return &Result{
+		Columns: resp.Columns,
+		Rows:    resp.Rows,
+		Summary: ResultSummary{
+			Query:         query,
+			ServerTime:    time.Duration(resp.Summary.ExecutionTimeMS * float64(time.Millisecond)),
+			RoundTrip:     roundTrip,
+			NodesCreated:  resp.Summary.NodesCreated,
+			NodesDeleted:  resp.Summary.NodesDeleted,
+			EdgesCreated:  resp.Summary.EdgesCreated,
+			EdgesDeleted:  resp.Summary.EdgesDeleted,
+			PropertiesSet: resp.Summary.PropertiesSet,
+		},
+	}, nil
+}
+
+// Len returns the number of rows
+func (r *Result) Len() int {
+	return len(r.Rows)
+}
+
+// ColumnIndex returns the position of a column, or -1 if it does not exist
+func (r *Result) ColumnIndex(column string) int {
+	for i, c := range r.Columns {
+		if c == column {
+			return i
+		}
+	}
+	return -1
+}
+
+// Value decodes a single cell into dst, which must be a pointer
+func (r *Result) Value(row int, column string, dst interface{}) error {
+	raw, err := r.cell(row, column)
+	if err != nil {
+		return err
+	}
+	return decodeCell(raw, dst, column)
+}
+
+// ScanRow decodes a row into the struct pointed to by dst. Columns are
+// matched to fields by the `nendb` struct tag, then the `json` tag, then a
+// case-insensitive field name. Columns without a matching field are ignored.
+func (r *Result) ScanRow(row int, dst interface{}) error {
+	if row < 0 || row >= len(r.Rows) {
+		return errors.NewValidationError("Row index out of range", map[string]interface{}{"row": row, "rows": len(r.Rows)})
+	}
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.NewValidationError("ScanRow requires a non-nil pointer to a struct", map[string]interface{}{"type": fmt.Sprintf("%T", dst)})
+	}
+	return r.scanInto(row, v.Elem(), structFields(v.Elem().Type()))
+}
+
+// ScanAll decodes every row into the slice of structs pointed to by dst
+func (r *Result) ScanAll(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return errors.NewValidationError("ScanAll requires a non-nil pointer to a slice", map[string]interface{}{"type": fmt.Sprintf("%T", dst)})
+	}
+
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return errors.NewValidationError("ScanAll requires a slice of structs", map[string]interface{}{"type": fmt.Sprintf("%T", dst)})
+	}
+
+	fields := structFields(structType)
+	out := reflect.MakeSlice(slice.Type(), 0, len(r.Rows))
+	for i := range r.Rows {
+		elem := reflect.New(structType)
+		if err := r.scanInto(i, elem.Elem(), fields); err != nil {
+			return err
+		}
+		if isPtr {
+			out = reflect.Append(out, elem)
+		} else {
+			out = reflect.Append(out, elem.Elem())
+		}
+	}
+	slice.Set(out)
+	return nil
+}
+
+// Nodes decodes a column holding nodes
+func (r *Result) Nodes(column string) ([]types.GraphNode, error) {
+	nodes := make([]types.GraphNode, len(r.Rows))
+	for i := range r.Rows {
+		if err := r.Value(i, column, &nodes[i]); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// Edges decodes a column holding edges
+func (r *Result) Edges(column string) ([]types.GraphEdge, error) {
+	edges := make([]types.GraphEdge, len(r.Rows))
+	for i := range r.Rows {
+		if err := r.Value(i, column, &edges[i]); err != nil {
+			return nil, err
+		}
+	}
+	return edges, nil
+}
+
+// Paths decodes a column holding paths
+func (r *Result) Paths(column string) ([]types.Path, error) {
+	paths := make([]types.Path, len(r.Rows))
+	for i := range r.Rows {
+		if err := r.Value(i, column, &paths[i]); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// cell returns the raw value at row and column
+func (r *Result) cell(row int, column string) (json.RawMessage, error) {
+	if row < 0 || row >= len(r.Rows) {
+		return nil, errors.NewValidationError("Row index out of range", map[string]interface{}{"row": row, "rows": len(r.Rows)})
+	}
+	col := r.ColumnIndex(column)
+	if col < 0 {
+		return nil, errors.NewValidationError("Unknown result column", map[string]interface{}{"column": column, "columns": r.Columns})
+	}
+	if col >= len(r.Rows[row]) {
+		return nil, errors.NewResponseError("Result row is shorter than its columns", map[string]interface{}{"row": row, "column": column})
+	}
+	return r.Rows[row][col], nil
+}
+
+// scanInto decodes a row into the struct value v
+func (r *Result) scanInto(row int, v reflect.Value, fields map[string]int) error {
+	for col, name := range r.Columns {
+		idx, ok := fields[name]
+		if !ok {
+			idx, ok = fields[strings.ToLower(name)]
+		}
+		if !ok || col >= len(r.Rows[row]) {
+			continue
+		}
+		if err := decodeCell(r.Rows[row][col], v.Field(idx).Addr().Interface(), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structFields maps column names to the index of the field receiving them.
+// Exact tag names are stored as-is; field names are stored lower-cased.
+func structFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := ""
+		for _, key := range []string{"nendb", "json"} {
+			if tag, ok := f.Tag.Lookup(key); ok {
+				name, _, _ = strings.Cut(tag, ",")
+				break
+			}
+		}
+		switch name {
+		case "-":
+			continue
+		case "":
+			fields[strings.ToLower(f.Name)] = i
+		default:
+			fields[name] = i
+		}
+	}
+	return fields
+}
+
+// decodeCell unmarshals a raw cell into dst
+func decodeCell(raw json.RawMessage, dst interface{}, column string) error {
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return errors.NewResponseError("Failed to decode result column", map[string]interface{}{"column": column, "error": err.Error()})
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+const sampleResult = `{
+	"columns": ["name", "age", "n", "p"],
+	"rows": [
+		["Alice", 30, {"id": 1, "labels": ["Person"], "properties": {"name": "Alice"}},
+			{"nodes": [{"id": 1}, {"id": 2}], "edges": [{"id": 9, "source": 1, "target": 2, "type": "KNOWS"}]}],
+		["Bob", 9007199254740993, {"id": 2, "labels": ["Person"], "properties": {"name": "Bob"}},
+			{"nodes": [{"id": 2}], "edges": []}]
+	],
+	"summary": {"execution_time_ms": 1.5, "nodes_created": 2}
+}`
+
+func TestParseResult(t *testing.T) {
+	result, err := parseResult("MATCH (n) RETURN n", []byte(sampleResult), 3*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+
+	if result.Len() != 2 || len(result.Columns) != 4 {
+		t.Errorf("Expected 2 rows and 4 columns, got %d and %d", result.Len(), len(result.Columns))
+	}
+	if result.Summary.ServerTime != 1500*time.Microsecond {
+		t.Errorf("Expected server time 1.5ms, got %v", result.Summary.ServerTime)
+	}
+	if result.Summary.RoundTrip != 3*time.Millisecond {
+		t.Errorf("Expected round trip 3ms, got %v", result.Summary.RoundTrip)
+	}
+	if result.Summary.NodesCreated != 2 {
+		t.Errorf("Expected 2 nodes created, got %d", result.Summary.NodesCreated)
+	}
+
+	// Raw cells decode losslessly into the requested type
+	var age int64
+	if err := result.Value(1, "age", &age); err != nil {
+		t.Fatalf("Failed to decode value: %v", err)
+	}
+	if age != 9007199254740993 {
+		t.Errorf("Expected age 9007199254740993, got %d", age)
+	}
+
+	if err := result.Value(0, "missing", &age); err == nil {
+		t.Error("Expected error for unknown column, got nil")
+	}
+	if err := result.Value(5, "age", &age); err == nil {
+		t.Error("Expected error for out of range row, got nil")
+	}
+}
+
+func TestResultScan(t *testing.T) {
+	result, err := parseResult("", []byte(sampleResult), 0)
+	if err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+
+	type person struct {
+		Name   string
+		Years  int64  `nendb:"age"`
+		Ignore string `nendb:"-"`
+	}
+
+	var p person
+	if err := result.ScanRow(0, &p); err != nil {
+		t.Fatalf("Failed to scan row: %v", err)
+	}
+	if p.Name != "Alice" || p.Years != 30 {
+		t.Errorf("Expected {Alice 30}, got %+v", p)
+	}
+
+	var people []*person
+	if err := result.ScanAll(&people); err != nil {
+		t.Fatalf("Failed to scan all rows: %v", err)
+	}
+	if len(people) != 2 || people[1].Name != "Bob" {
+		t.Errorf("Expected 2 people ending with Bob, got %+v", people)
+	}
+
+	if err := result.ScanRow(0, p); err == nil {
+		t.Error("Expected error scanning into non-pointer, got nil")
+	}
+	if err := result.ScanAll(&[]int{}); err == nil {
+		t.Error("Expected error scanning into slice of non-structs, got nil")
+	}
+}
+
+func TestResultGraphHelpers(t *testing.T) {
+	result, err := parseResult("", []byte(sampleResult), 0)
+	if err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+
+	nodes, err := result.Nodes("n")
+	if err != nil {
+		t.Fatalf("Failed to decode nodes: %v", err)
+	}
+	if len(nodes) != 2 || nodes[1].Properties["name"] != "Bob" {
+		t.Errorf("Expected nodes Alice and Bob, got %+v", nodes)
+	}
+
+	paths, err := result.Paths("p")
+	if err != nil {
+		t.Fatalf("Failed to decode paths: %v", err)
+	}
+	if paths[0].Len() != 1 || paths[0].Edges[0].Type != "KNOWS" {
+		t.Errorf("Expected 1-hop KNOWS path, got %+v", paths[0])
+	}
+	if paths[1].Len() != 0 {
+		t.Errorf("Expected empty path, got %+v", paths[1])
+	}
+}
+
+func TestQueryReturnsResult(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	a := srv.AddNode(nil, nil)
+	b := srv.AddNode(nil, nil)
+	srv.AddEdge(a.ID, b.ID, "KNOWS", nil)
+
+	result, err := client.Query(context.Background(), "MATCH ()-[r:KNOWS]->() RETURN r", nil)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	edges, err := result.Edges("r")
+	if err != nil {
+		t.Fatalf("Failed to decode edges: %v", err)
+	}
+	if len(edges) != 1 || edges[0].Source != a.ID {
+		t.Errorf("Expected 1 edge from %d, got %+v", a.ID, edges)
+	}
+	if result.Summary.Query != "MATCH ()-[r:KNOWS]->() RETURN r" {
+		t.Errorf("Expected summary to carry the query, got %q", result.Summary.Query)
+	}
+	if result.Summary.RoundTrip <= 0 {
+		t.Error("Expected a positive round trip time")
+	}
+}
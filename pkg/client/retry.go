@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// DefaultMaxRetryDelay caps the backoff computed by ExponentialBackoff
+const DefaultMaxRetryDelay = 30 * time.Second
+
+// RetryPolicy decides whether a failed attempt is retried and how long to wait
+// before the next one. attempt is the number of attempts made so far. Exactly
+// one of resp and err is non-nil; when resp is set its body has already been
+// consumed, but its status code and headers are available.
+type RetryPolicy interface {
+	NextRetry(attempt int, method string, resp *http.Response, err error) (time.Duration, bool)
+}
+
+// ExponentialBackoff retries requests classified by Retryable, waiting
+// BaseDelay * Multiplier^(attempt-1) between attempts, capped at MaxDelay and
+// randomised by Jitter. A Retry-After header longer than the computed delay
+// takes precedence, up to MaxDelay.
+type ExponentialBackoff struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is the delay before the first retry
+	BaseDelay time.Duration
+	// MaxDelay caps the computed delay and any Retry-After wait; zero means
+	// DefaultMaxRetryDelay
+	MaxDelay time.Duration
+	// Multiplier grows the delay between retries; values below 1 mean 2
+	Multiplier float64
+	// Jitter is the fraction of each delay that is randomised, from 0 to 1
+	Jitter float64
+}
+
+// NewExponentialBackoff returns the policy used when ClientConfig.RetryPolicy
+// is nil
+func NewExponentialBackoff(maxRetries int, baseDelay time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxRetries: maxRetries,
+		BaseDelay:  baseDelay,
+		MaxDelay:   DefaultMaxRetryDelay,
+		Multiplier: 2,
+		Jitter:     0.5,
+	}
+}
+
+// NextRetry implements RetryPolicy
+func (b *ExponentialBackoff) NextRetry(attempt int, method string, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt > b.MaxRetries || !Retryable(method, resp, err) {
+		return 0, false
+	}
+
+	delay := b.backoff(attempt)
+	if resp != nil {
+		if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok && after > delay {
+			delay = min(after, b.maxDelay())
+		}
+	}
+	return delay, true
+}
+
+func (b *ExponentialBackoff) backoff(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	maxDelay := b.maxDelay()
+
+	delay := float64(b.BaseDelay) * math.Pow(multiplier, float64(attempt-1))
+	if delay > float64(maxDelay) {
+		delay = float64(maxDelay)
+	}
+	if jitter := math.Min(math.Max(b.Jitter, 0), 1); jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+func (b *ExponentialBackoff) maxDelay() time.Duration {
+	if b.MaxDelay <= 0 {
+		return DefaultMaxRetryDelay
+	}
+	return b.MaxDelay
+}
+
+// Retryable reports whether a failed attempt may safely be repeated.
+// Connection failures that happened before the request was sent and 429, 502,
+// 503 and 504 responses are retried for every method. Other transport errors
+// and 500 responses are retried only for idempotent methods, since the server
+// may already have applied the request. Context cancellation is never retried.
+func Retryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return isConnectFailure(err) || isIdempotent(method)
+	}
+	if resp == nil {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusInternalServerError:
+		return isIdempotent(method)
+	}
+	return false
+}
+
+// isIdempotent reports whether repeating a request with method has the same
+// effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isConnectFailure reports whether err happened while connecting, before any
+// part of the request reached the server
+func isConnectFailure(err error) bool {
+	if stderrors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
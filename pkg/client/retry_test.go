@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+// statusServer answers with the given statuses in order, then 200 OK
+func statusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n <= len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n-1])
+			fmt.Fprint(w, `{"code": "UNAVAILABLE", "message": "try again"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status": "healthy"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newRetryClient(t *testing.T, url string, maxRetries int) *NenDBClient {
+	t.Helper()
+	client, err := NewClient(&ClientConfig{
+		BaseURL:        url,
+		Timeout:        5 * time.Second,
+		MaxRetries:     maxRetries,
+		RetryDelay:     time.Millisecond,
+		SkipValidation: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestRetryClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		statuses []int
+		wantErr  bool
+		calls    int32
+	}{
+		{"GET retries 500", "GET", []int{500, 500}, false, 3},
+		{"POST does not retry 500", "POST", []int{500}, true, 1},
+		{"POST retries 503", "POST", []int{503, 502, 504}, false, 4},
+		{"GET does not retry 404", "GET", []int{404}, true, 1},
+		{"retries are bounded", "GET", []int{503, 503, 503, 503, 503}, true, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := statusServer(t, nil, tt.statuses...)
+			client := newRetryClient(t, srv.URL, 3)
+
+			_, err := client.makeRequest(context.Background(), op("Test"), tt.method, "/health", nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.calls {
+				t.Errorf("Expected %d attempts, got %d", tt.calls, got)
+			}
+		})
+	}
+}
+
+func TestRetryRetriesConnectionRefusedForPOST(t *testing.T) {
+	// Grab a free port, then close the listener so connections are refused
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	policy := &countingPolicy{RetryPolicy: NewExponentialBackoff(2, time.Millisecond)}
+	client := newRetryClient(t, url, 0)
+	client.retryPolicy = policy
+
+	if _, err := client.makeRequest(context.Background(), op("Test"), "POST", "/nodes", map[string]interface{}{}, nil); err == nil {
+		t.Fatal("Expected error from closed server, got nil")
+	}
+	if policy.retries != 2 {
+		t.Errorf("Expected 2 retries for refused connection, got %d", policy.retries)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"1"}}
+	srv, calls := statusServer(t, header, http.StatusTooManyRequests)
+	client := newRetryClient(t, srv.URL, 1)
+
+	start := time.Now()
+	if _, err := client.makeRequest(context.Background(), op("Test"), "GET", "/health", nil, nil); err != nil {
+		t.Fatalf("Expected success after retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait for Retry-After, retried after %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"60"}}
+	srv, calls := statusServer(t, header, 503, 503)
+	client := newRetryClient(t, srv.URL, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.makeRequest(ctx, op("Test"), "GET", "/health", nil, nil); err == nil {
+		t.Fatal("Expected error when context expires during backoff, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected backoff to stop with the context, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestExponentialBackoffDelays(t *testing.T) {
+	b := &ExponentialBackoff{MaxRetries: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2}
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+
+	want := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, w := range want {
+		delay, ok := b.NextRetry(i+1, "GET", resp, nil)
+		if !ok {
+			t.Fatalf("Expected attempt %d to be retried", i+1)
+		}
+		if delay != w*time.Millisecond {
+			t.Errorf("Attempt %d: expected delay %v, got %v", i+1, w*time.Millisecond, delay)
+		}
+	}
+	if _, ok := b.NextRetry(11, "GET", resp, nil); ok {
+		t.Error("Expected no retry past MaxRetries")
+	}
+
+	resp.Header.Set("Retry-After", "3600")
+	if delay, _ := b.NextRetry(1, "GET", resp, nil); delay != time.Second {
+		t.Errorf("Expected Retry-After to be capped at MaxDelay, got %v", delay)
+	}
+	resp.Header.Del("Retry-After")
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay, _ := b.NextRetry(3, "GET", resp, nil)
+		if delay < 200*time.Millisecond || delay > 400*time.Millisecond {
+			t.Fatalf("Expected jittered delay within [200ms, 400ms], got %v", delay)
+		}
+	}
+}
+
+func TestRetryAfterParsing(t *testing.T) {
+	if d, ok := retryAfter("3"); !ok || d != 3*time.Second {
+		t.Errorf("Expected 3s, got %v (%v)", d, ok)
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d, ok := retryAfter(future); !ok || d < 59*time.Minute {
+		t.Errorf("Expected about 1h, got %v (%v)", d, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("Expected invalid Retry-After to be ignored")
+	}
+}
+
+func TestRetriedWritesCarryFullPayload(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newRetryClient(t, srv.URL, 3)
+	ctx := context.Background()
+
+	props := map[string]interface{}{"name": "Alice", "bio": string(make([]byte, 64*1024))}
+	srv.FailNext(2, http.StatusServiceUnavailable)
+	node, err := client.CreateNode(ctx, []string{"Person"}, props)
+	if err != nil {
+		t.Fatalf("Expected create to succeed after retries, got %v", err)
+	}
+	if got := srv.Requests(); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+	stored := srv.Node(node.ID)
+	if stored.Properties["name"] != "Alice" || len(stored.Properties["bio"].(string)) != 64*1024 {
+		t.Errorf("Expected retried create to carry the full payload, got labels %v and %d properties", stored.Labels, len(stored.Properties))
+	}
+
+	srv.FailNext(1, http.StatusInternalServerError)
+	if _, err := client.UpdateNode(ctx, node.ID, []string{"Person", "Admin"}, map[string]interface{}{"age": 31}); err != nil {
+		t.Fatalf("Expected idempotent update to be retried, got %v", err)
+	}
+	if stored := srv.Node(node.ID); stored.Properties["age"] != json.Number("31") || len(stored.Labels) != 2 {
+		t.Errorf("Expected retried update to carry the full payload, got %v %v", stored.Labels, stored.Properties)
+	}
+
+	srv.FailNext(1, http.StatusBadGateway)
+	result, err := client.Query(ctx, "MATCH (n:Admin) RETURN n", nil)
+	if err != nil {
+		t.Fatalf("Expected query to succeed after retry, got %v", err)
+	}
+	if result.Len() != 1 {
+		t.Errorf("Expected 1 row from retried query, got %d", result.Len())
+	}
+}
+
+func TestResponseBodiesClosedPerAttempt(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newRetryClient(t, srv.URL, 3)
+	transport := &trackingTransport{t: t}
+	client.httpClient = &http.Client{Transport: transport}
+
+	srv.FailNext(3, http.StatusServiceUnavailable)
+	if _, err := client.CreateNode(context.Background(), nil, map[string]interface{}{"k": "v"}); err != nil {
+		t.Fatalf("Expected create to succeed after retries, got %v", err)
+	}
+	if transport.attempts != 4 {
+		t.Errorf("Expected 4 attempts, got %d", transport.attempts)
+	}
+	if transport.open != 0 {
+		t.Errorf("Expected all response bodies closed, %d still open", transport.open)
+	}
+}
+
+// trackingTransport fails the test if a new attempt starts while an earlier
+// response body is still open
+type trackingTransport struct {
+	t        *testing.T
+	mu       sync.Mutex
+	attempts int
+	open     int
+}
+
+func (tr *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr.mu.Lock()
+	tr.attempts++
+	if tr.open != 0 {
+		tr.t.Errorf("Attempt %d started with %d response bodies still open", tr.attempts, tr.open)
+	}
+	tr.mu.Unlock()
+
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	tr.mu.Lock()
+	tr.open++
+	tr.mu.Unlock()
+	resp.Body = &trackedBody{ReadCloser: resp.Body, tr: tr}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	tr   *trackingTransport
+	once sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(func() {
+		b.tr.mu.Lock()
+		b.tr.open--
+		b.tr.mu.Unlock()
+	})
+	return b.ReadCloser.Close()
+}
+
+type countingPolicy struct {
+	RetryPolicy
+	retries int
+}
+
+func (p *countingPolicy) NextRetry(attempt int, method string, resp *http.Response, err error) (time.Duration, bool) {
+	delay, ok := p.RetryPolicy.NextRetry(attempt, method, resp, err)
+	if ok {
+		p.retries++
+	}
+	return delay, ok
+}
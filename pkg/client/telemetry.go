@@ -0,0 +1,259 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+)
+
+// Headers that carry the W3C trace context to the server
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// Metrics recorded through ClientConfig.Meter
+const (
+	// MetricRequestDuration is a histogram of request latency in seconds,
+	// from the first attempt to the final response, retries included
+	MetricRequestDuration = "nendb.client.request.duration"
+	// MetricRetries counts retried attempts
+	MetricRetries = "nendb.client.retries"
+	// MetricErrors counts failed requests by error.type
+	MetricErrors = "nendb.client.errors"
+)
+
+// Tracer starts a span for each request the client sends. It covers the part
+// of the OpenTelemetry tracing API the client needs, so an OpenTelemetry
+// tracer plugs in through a small adapter without the driver depending on
+// the OpenTelemetry modules.
+type Tracer interface {
+	// Start begins a client span as a child of any span in ctx
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError marks the span as failed
+	RecordError(err error)
+	End()
+	// SpanContext identifies the span to the server in the traceparent header
+	SpanContext() SpanContext
+}
+
+// SpanContext is the W3C trace context of a span
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+	// TraceState is sent in the tracestate header when not empty
+	TraceState string
+}
+
+// IsValid reports whether sc has non-zero trace and span IDs
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Traceparent formats sc as a version 00 traceparent header value
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// Meter creates the instruments behind the client's metrics. Like Tracer it
+// mirrors a subset of the OpenTelemetry API.
+type Meter interface {
+	Float64Histogram(name, unit, description string) Float64Histogram
+	Int64Counter(name, unit, description string) Int64Counter
+}
+
+// Float64Histogram records a distribution of values
+type Float64Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Int64Counter records a monotonic count
+type Int64Counter interface {
+	Add(ctx context.Context, value int64, attrs ...Attribute)
+}
+
+// Attribute is a key-value pair attached to spans and measurements. Values
+// are strings, ints or bools.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// operation names the client method behind a request, for spans and metrics
+type operation struct {
+	name  string
+	attrs []Attribute
+}
+
+func op(name string, attrs ...Attribute) operation {
+	return operation{name: name, attrs: attrs}
+}
+
+func nodeAttr(id int) Attribute {
+	return Attribute{Key: "nendb.node.id", Value: id}
+}
+
+func edgeAttr(id int) Attribute {
+	return Attribute{Key: "nendb.edge.id", Value: id}
+}
+
+// pathAttrs describes a path between two nodes
+func pathAttrs(source, target int) []Attribute {
+	return []Attribute{
+		{Key: "nendb.source_node.id", Value: source},
+		{Key: "nendb.target_node.id", Value: target},
+	}
+}
+
+// telemetry holds the configured tracer and instruments. A nil *telemetry
+// records nothing.
+type telemetry struct {
+	tracer   Tracer
+	server   string
+	duration Float64Histogram
+	retries  Int64Counter
+	errors   Int64Counter
+}
+
+func newTelemetry(tracer Tracer, meter Meter, baseURL string) *telemetry {
+	if tracer == nil && meter == nil {
+		return nil
+	}
+	t := &telemetry{tracer: tracer}
+	if u, err := url.Parse(baseURL); err == nil {
+		t.server = u.Hostname()
+	}
+	if meter != nil {
+		t.duration = meter.Float64Histogram(MetricRequestDuration, "s", "Duration of NenDB client requests, including retries")
+		t.retries = meter.Int64Counter(MetricRetries, "{retry}", "Retried NenDB client request attempts")
+		t.errors = meter.Int64Counter(MetricErrors, "{error}", "Failed NenDB client requests")
+	}
+	return t
+}
+
+// callTrace follows a single request through its attempts. Its methods do
+// nothing on a nil *callTrace.
+type callTrace struct {
+	t        *telemetry
+	op       operation
+	method   string
+	span     Span
+	start    time.Time
+	attempts int
+	status   int
+}
+
+// start begins tracing a request
+func (t *telemetry) start(ctx context.Context, op operation, method, endpoint string) (context.Context, *callTrace) {
+	if t == nil {
+		return ctx, nil
+	}
+	ct := &callTrace{t: t, op: op, method: method, start: time.Now()}
+	if t.tracer != nil {
+		attrs := append([]Attribute{
+			{Key: "db.system.name", Value: "nendb"},
+			{Key: "db.operation.name", Value: op.name},
+			{Key: "http.request.method", Value: method},
+			{Key: "url.path", Value: endpoint},
+			{Key: "server.address", Value: t.server},
+		}, op.attrs...)
+		ctx, ct.span = t.tracer.Start(ctx, "nendb."+op.name, attrs...)
+	}
+	return ctx, ct
+}
+
+// inject adds the span's trace context to h
+func (ct *callTrace) inject(h http.Header) {
+	if ct == nil || ct.span == nil {
+		return
+	}
+	sc := ct.span.SpanContext()
+	if !sc.IsValid() {
+		return
+	}
+	h.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		h.Set(TracestateHeader, sc.TraceState)
+	}
+}
+
+// attempt records the outcome of one attempt; resp is nil when no response
+// was received
+func (ct *callTrace) attempt(resp *http.Response) {
+	if ct == nil {
+		return
+	}
+	ct.attempts++
+	ct.status = 0
+	if resp != nil {
+		ct.status = resp.StatusCode
+	}
+}
+
+// retry records that another attempt follows
+func (ct *callTrace) retry(ctx context.Context) {
+	if ct == nil || ct.t.retries == nil {
+		return
+	}
+	ct.t.retries.Add(ctx, 1, ct.metricAttrs()...)
+}
+
+// end finishes the span and records the request's metrics
+func (ct *callTrace) end(ctx context.Context, err error) {
+	if ct == nil {
+		return
+	}
+	errType := ""
+	if err != nil {
+		errType = errors.TypeName(err)
+		if errType == "" {
+			errType = "_OTHER"
+		}
+	}
+
+	var outcome []Attribute
+	if ct.status != 0 {
+		outcome = append(outcome, Attribute{Key: "http.response.status_code", Value: ct.status})
+	}
+	if errType != "" {
+		outcome = append(outcome, Attribute{Key: "error.type", Value: errType})
+	}
+
+	if ct.span != nil {
+		ct.span.SetAttributes(append([]Attribute{{Key: "nendb.attempts", Value: ct.attempts}}, outcome...)...)
+		if err != nil {
+			ct.span.RecordError(err)
+		}
+		ct.span.End()
+	}
+
+	attrs := append(ct.metricAttrs(), outcome...)
+	if ct.t.duration != nil {
+		ct.t.duration.Record(ctx, time.Since(ct.start).Seconds(), attrs...)
+	}
+	if err != nil && ct.t.errors != nil {
+		ct.t.errors.Add(ctx, 1, attrs...)
+	}
+}
+
+// metricAttrs are the low-cardinality attributes shared by every measurement
+func (ct *callTrace) metricAttrs() []Attribute {
+	return []Attribute{
+		{Key: "db.operation.name", Value: ct.op.name},
+		{Key: "http.request.method", Value: ct.method},
+	}
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+type fakeSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+	sc    SpanContext
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *fakeSpan) RecordError(err error)    { s.err = err }
+func (s *fakeSpan) End()                     { s.ended = true }
+func (s *fakeSpan) SpanContext() SpanContext { return s.sc }
+
+// fakeTracer records spans, giving each a distinct span ID
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &fakeSpan{name: name, attrs: map[string]interface{}{}}
+	span.SetAttributes(attrs...)
+	span.sc.TraceID = [16]byte{0x4b, 0xf9, 15: 0x36}
+	span.sc.SpanID = [8]byte{7: byte(len(t.spans) + 1)}
+	span.sc.Sampled = true
+	span.sc.TraceState = "nendb=test"
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+type measurement struct {
+	value float64
+	attrs map[string]interface{}
+}
+
+// fakeMeter records every measurement by instrument name
+type fakeMeter struct {
+	mu     sync.Mutex
+	values map[string][]measurement
+}
+
+type fakeInstrument struct {
+	meter *fakeMeter
+	name  string
+}
+
+func (m *fakeMeter) Float64Histogram(name, unit, description string) Float64Histogram {
+	return fakeInstrument{m, name}
+}
+
+func (m *fakeMeter) Int64Counter(name, unit, description string) Int64Counter {
+	return fakeInstrument{m, name}
+}
+
+func (i fakeInstrument) Record(ctx context.Context, value float64, attrs ...Attribute) {
+	i.meter.mu.Lock()
+	defer i.meter.mu.Unlock()
+	m := measurement{value: value, attrs: map[string]interface{}{}}
+	for _, a := range attrs {
+		m.attrs[a.Key] = a.Value
+	}
+	i.meter.values[i.name] = append(i.meter.values[i.name], m)
+}
+
+func (i fakeInstrument) Add(ctx context.Context, value int64, attrs ...Attribute) {
+	i.Record(ctx, float64(value), attrs...)
+}
+
+func TestTracingSpansAndPropagation(t *testing.T) {
+	fake := nendbtest.NewServer()
+	defer fake.Close()
+	var traceparents, tracestates []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get(TraceparentHeader))
+		tracestates = append(tracestates, r.Header.Get(TracestateHeader))
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	tracer := &fakeTracer{}
+	config := DefaultConfig()
+	config.BaseURL = srv.URL
+	config.RetryDelay = 0
+	config.SkipValidation = true
+	config.Tracer = tracer
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	node, err := client.CreateNode(ctx, []string{"Person"}, nil)
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	fake.FailNext(1, http.StatusServiceUnavailable)
+	if _, err := client.GetNode(ctx, node.ID); err != nil {
+		t.Fatalf("GetNode failed: %v", err)
+	}
+	if _, err := client.GetEdge(ctx, 99); err == nil {
+		t.Fatal("Expected GetEdge to fail")
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("Expected a span per call, got %d", len(tracer.spans))
+	}
+	get := tracer.spans[1]
+	if get.name != "nendb.GetNode" || !get.ended || get.err != nil {
+		t.Errorf("Unexpected span %+v", get)
+	}
+	for key, want := range map[string]interface{}{
+		"db.system.name":            "nendb",
+		"db.operation.name":         "GetNode",
+		"http.request.method":       "GET",
+		"url.path":                  "/nodes/1",
+		"nendb.node.id":             node.ID,
+		"nendb.attempts":            2,
+		"http.response.status_code": 200,
+	} {
+		if get.attrs[key] != want {
+			t.Errorf("Expected span attribute %s=%v, got %v", key, want, get.attrs[key])
+		}
+	}
+
+	failed := tracer.spans[2]
+	if failed.err == nil || failed.attrs["error.type"] != "NenDBResponseError" || failed.attrs["nendb.edge.id"] != 99 {
+		t.Errorf("Expected the failed call to be recorded on its span, got %+v", failed)
+	}
+
+	// Both attempts of GetNode carry its span's context
+	want := "00-4bf90000000000000000000000000036-0000000000000002-01"
+	if len(traceparents) != 4 || traceparents[1] != want || traceparents[2] != want || tracestates[1] != "nendb=test" {
+		t.Errorf("Unexpected propagation headers %q %q", traceparents, tracestates)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	meter := &fakeMeter{values: map[string][]measurement{}}
+	config := DefaultConfig()
+	config.BaseURL = srv.URL
+	config.RetryDelay = 0
+	config.Meter = meter
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	srv.FailNext(2, http.StatusServiceUnavailable)
+	if _, err := client.GetStatistics(context.Background()); err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if _, err := client.GetNode(context.Background(), 1); err == nil {
+		t.Fatal("Expected GetNode to fail")
+	}
+
+	durations := meter.values[MetricRequestDuration]
+	// Includes the health check made by NewClient
+	if len(durations) != 3 || durations[1].attrs["db.operation.name"] != "GetStatistics" || durations[1].value <= 0 {
+		t.Errorf("Unexpected durations %+v", durations)
+	}
+	retries := meter.values[MetricRetries]
+	if len(retries) != 2 || retries[0].attrs["db.operation.name"] != "GetStatistics" {
+		t.Errorf("Expected 2 retries, got %+v", retries)
+	}
+	errs := meter.values[MetricErrors]
+	if len(errs) != 1 || errs[0].attrs["error.type"] != "NenDBResponseError" || errs[0].attrs["http.response.status_code"] != 404 {
+		t.Errorf("Expected one error by type, got %+v", errs)
+	}
+}
+
+func TestTraceparent(t *testing.T) {
+	sc := SpanContext{TraceID: [16]byte{0: 0xab, 15: 0x01}, SpanID: [8]byte{0: 0xcd}}
+	if got := sc.Traceparent(); got != "00-ab000000000000000000000000000001-cd00000000000000-00" {
+		t.Errorf("Unexpected traceparent %q", got)
+	}
+	if (SpanContext{}).IsValid() || !sc.IsValid() {
+		t.Error("Expected only span contexts with both IDs to be valid")
+	}
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig configures TLS for connections to an https:// server
+type TLSConfig struct {
+	// CAFile is a PEM bundle of CAs trusted in addition to the system pool
+	CAFile string
+	// CertFile and KeyFile hold a PEM client certificate and key for mutual TLS
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name used to verify the server certificate
+	ServerName string
+	// InsecureSkipVerify disables server certificate verification. Use it
+	// only for testing.
+	InsecureSkipVerify bool
+}
+
+// Build loads the configured files into a *tls.Config
+func (c *TLSConfig) Build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate requires both CertFile and KeyFile")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
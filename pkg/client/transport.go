@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Transport defaults used for zero TransportConfig fields
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 32
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultKeepAlive           = 30 * time.Second
+	DefaultDialTimeout         = 30 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// TransportConfig tunes the connection pool and dialer of the HTTP transport
+// built by NewClient. Zero fields use the defaults above.
+type TransportConfig struct {
+	// MaxIdleConns limits idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits idle connections kept for the server. The
+	// net/http default of 2 makes busy clients close and redial connections,
+	// which can exhaust ephemeral ports.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits connections to the server, including those in
+	// use; requests wait for a free connection. Zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections that have been idle this long
+	IdleConnTimeout time.Duration
+
+	// KeepAlive is the TCP keep-alive period; negative disables TCP
+	// keep-alive probes
+	KeepAlive time.Duration
+	// DisableKeepAlives closes every connection after a single request
+	DisableKeepAlives bool
+	// DialTimeout limits how long establishing a TCP connection may take
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout limits how long the TLS handshake may take
+	TLSHandshakeTimeout time.Duration
+
+	// DisableHTTP2 keeps https:// connections on HTTP/1.1. By default
+	// HTTP/2 is negotiated when the server supports it.
+	DisableHTTP2 bool
+
+	// Proxy selects the proxy for each request, as http.Transport.Proxy
+	// does. When both Proxy and ProxyURL are unset the HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables are used.
+	Proxy func(*http.Request) (*url.URL, error)
+	// ProxyURL sends every request through this proxy, for example
+	// http://proxy.internal:3128. Proxy takes precedence.
+	ProxyURL string
+}
+
+// Build returns an *http.Transport configured from c
+func (c *TransportConfig) Build() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = orDefault(c.MaxIdleConns, DefaultMaxIdleConns)
+	transport.MaxIdleConnsPerHost = orDefault(c.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost)
+	transport.MaxConnsPerHost = c.MaxConnsPerHost
+	transport.IdleConnTimeout = orDefault(c.IdleConnTimeout, DefaultIdleConnTimeout)
+	transport.TLSHandshakeTimeout = orDefault(c.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout)
+	transport.DisableKeepAlives = c.DisableKeepAlives
+
+	dialer := &net.Dialer{
+		Timeout:   orDefault(c.DialTimeout, DefaultDialTimeout),
+		KeepAlive: orDefault(c.KeepAlive, DefaultKeepAlive),
+	}
+	transport.DialContext = dialer.DialContext
+
+	if c.DisableHTTP2 {
+		// A non-nil, empty TLSNextProto turns off HTTP/2 negotiation
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	switch {
+	case c.Proxy != nil:
+		transport.Proxy = c.Proxy
+	case c.ProxyURL != "":
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", c.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
+}
+
+func orDefault[T int | time.Duration](value, def T) T {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// Stats is a snapshot of the client's request and connection activity
+type Stats struct {
+	// Requests counts HTTP requests sent, including retries
+	Requests int64
+	// InFlight is the number of requests waiting for a response
+	InFlight int64
+	// NewConns and ReusedConns count requests that dialled a new connection
+	// and requests that reused a pooled one
+	NewConns    int64
+	ReusedConns int64
+	// OpenConns and IdleConns are the connections currently open and those
+	// not serving a request. They are only tracked for the transport built
+	// by NewClient, not for a custom HTTPClient.
+	OpenConns int
+	IdleConns int
+}
+
+// Stats returns a snapshot of the client's request and connection pool
+// activity
+func (c *NenDBClient) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// connStats collects the counters behind Stats
+type connStats struct {
+	requests atomic.Int64
+	inFlight atomic.Int64
+	newConns atomic.Int64
+	reused   atomic.Int64
+	// conns holds the open *trackedConn values
+	conns sync.Map
+}
+
+func (s *connStats) snapshot() Stats {
+	stats := Stats{
+		Requests:    s.requests.Load(),
+		InFlight:    s.inFlight.Load(),
+		NewConns:    s.newConns.Load(),
+		ReusedConns: s.reused.Load(),
+	}
+	s.conns.Range(func(key, _ interface{}) bool {
+		stats.OpenConns++
+		if key.(*trackedConn).active.Load() == 0 {
+			stats.IdleConns++
+		}
+		return true
+	})
+	return stats
+}
+
+// trackDial wraps dial so that the connections it opens are counted
+func (s *connStats) trackDial(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		tc := &trackedConn{Conn: conn, stats: s}
+		s.conns.Store(tc, struct{}{})
+		return tc, nil
+	}
+}
+
+// trace returns a context that records which connection serves a request.
+// The returned release function must be called once the response body has
+// been closed.
+func (s *connStats) trace(ctx context.Context) (context.Context, func()) {
+	var conn atomic.Pointer[trackedConn]
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				s.reused.Add(1)
+			} else {
+				s.newConns.Add(1)
+			}
+			if tc := findTrackedConn(info.Conn); tc != nil {
+				tc.active.Add(1)
+				conn.Store(tc)
+			}
+		},
+	}
+	release := func() {
+		if tc := conn.Swap(nil); tc != nil {
+			tc.active.Add(-1)
+		}
+	}
+	return httptrace.WithClientTrace(ctx, trace), release
+}
+
+// trackedConn is a connection opened by the client's transport. active
+// counts the requests it is serving; HTTP/2 connections may serve several.
+type trackedConn struct {
+	net.Conn
+	stats  *connStats
+	active atomic.Int32
+	closed sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.closed.Do(func() { c.stats.conns.Delete(c) })
+	return c.Conn.Close()
+}
+
+// findTrackedConn unwraps TLS connections to find the tracked connection
+func findTrackedConn(conn net.Conn) *trackedConn {
+	for conn != nil {
+		if tc, ok := conn.(*trackedConn); ok {
+			return tc
+		}
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return nil
+		}
+		conn = wrapper.NetConn()
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func TestStatsReportConnectionReuse(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetStatistics(ctx); err != nil {
+			t.Fatalf("GetStatistics failed: %v", err)
+		}
+	}
+
+	stats := client.Stats()
+	// Requests include the health check made by NewClient
+	if stats.Requests != 6 || stats.InFlight != 0 {
+		t.Errorf("Expected 6 requests and none in flight, got %+v", stats)
+	}
+	if stats.NewConns != 1 || stats.ReusedConns != 5 || stats.OpenConns != 1 || stats.IdleConns != 1 {
+		t.Errorf("Expected one pooled connection to serve every request, got %+v", stats)
+	}
+}
+
+func TestTransportLimitsConnectionsPerHost(t *testing.T) {
+	var mu sync.Mutex
+	var active, peak int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = srv.URL
+	config.SkipValidation = true
+	config.Transport = &TransportConfig{MaxConnsPerHost: 2}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetStatistics(context.Background()); err != nil {
+				t.Errorf("GetStatistics failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent connections, got %d", peak)
+	}
+	if stats := client.Stats(); stats.NewConns > 2 || stats.OpenConns > 2 || stats.Requests != 8 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestTransportProxyURL(t *testing.T) {
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+		w.Write([]byte(`{"status": "healthy"}`))
+	}))
+	defer proxy.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = "http://nendb.internal:8080"
+	config.Transport = &TransportConfig{ProxyURL: proxy.URL}
+	if _, err := NewClient(config); err != nil {
+		t.Fatalf("Expected the health check to go through the proxy, got %v", err)
+	}
+	if got := proxied.Load(); got != "http://nendb.internal:8080/health" {
+		t.Errorf("Expected the proxy to receive the absolute URL, got %v", got)
+	}
+}
+
+func TestTransportHTTP2(t *testing.T) {
+	var proto atomic.Value
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto.Store(r.Proto)
+		w.Write([]byte(`{"status": "healthy"}`))
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, disable := range []bool{false, true} {
+		config := DefaultConfig()
+		config.BaseURL = srv.URL
+		config.TLS = &TLSConfig{InsecureSkipVerify: true}
+		config.Transport = &TransportConfig{DisableHTTP2: disable}
+		if _, err := NewClient(config); err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		want := "HTTP/2.0"
+		if disable {
+			want = "HTTP/1.1"
+		}
+		if got := proto.Load(); got != want {
+			t.Errorf("DisableHTTP2=%v: server saw %v, want %s", disable, got, want)
+		}
+	}
+}
+
+func TestTransportConfigValidation(t *testing.T) {
+	config := DefaultConfig()
+	config.SkipValidation = true
+	config.Transport = &TransportConfig{ProxyURL: "not a url"}
+	if _, err := NewClient(config); err == nil {
+		t.Error("Expected an error for an invalid proxy URL")
+	}
+
+	config.Transport = &TransportConfig{}
+	config.HTTPClient = &http.Client{}
+	if _, err := NewClient(config); err == nil {
+		t.Error("Expected an error when combining Transport with HTTPClient")
+	}
+
+	transport, err := (&TransportConfig{KeepAlive: -1}).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost || transport.IdleConnTimeout != DefaultIdleConnTimeout {
+		t.Errorf("Expected defaults for zero fields, got %d and %v", transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
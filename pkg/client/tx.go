@@ -0,0 +1,364 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// TransactionHeader carries the transaction ID on requests made through a
+// server-side transaction
+const TransactionHeader = "X-NenDB-Transaction"
+
+// txKey is the context key holding the ID of a server-side transaction
+type txKey struct{}
+
+// Tx is a group of writes that are committed or rolled back together.
+//
+// When the server exposes the /transactions endpoints, every write is sent
+// with the transaction ID and the server applies or discards them as a unit.
+// Otherwise the transaction runs client-side: creates and updates are applied
+// immediately and recorded so Rollback can undo them, while deletes are held
+// back until Commit because a deleted node or edge cannot be recreated with
+// its ID. A failed delete during Commit rolls back the earlier writes. Other
+// clients can observe client-side writes before Commit.
+//
+// A Tx is safe for concurrent use but is meant to be used by one goroutine.
+type Tx struct {
+	client *NenDBClient
+	id     string
+
+	mu      sync.Mutex
+	done    bool
+	undo    []func(ctx context.Context) error
+	deletes []func(ctx context.Context) error
+}
+
+// BeginTx starts a transaction, using server-side transactions when the
+// server supports them
+func (c *NenDBClient) BeginTx(ctx context.Context, opts ...CallOption) (*Tx, error) {
+	if c.serverTx.Load() != txUnsupported {
+		respBody, err := c.makeRequest(ctx, op("BeginTx"), "POST", "/transactions", nil, nil, opts...)
+		switch {
+		case err == nil:
+			var resp struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(respBody, &resp); err != nil || resp.ID == "" {
+				return nil, errors.NewResponseError("Failed to parse transaction response", map[string]interface{}{"body": string(respBody)})
+			}
+			c.serverTx.Store(txSupported)
+			return &Tx{client: c, id: resp.ID}, nil
+		case !txEndpointMissing(err):
+			return nil, err
+		}
+		c.serverTx.Store(txUnsupported)
+	}
+	return &Tx{client: c}, nil
+}
+
+// WithTx runs fn in a transaction, committing it if fn returns nil and
+// rolling it back otherwise or if the commit fails. opts apply to beginning and ending the
+// transaction.
+func (c *NenDBClient) WithTx(ctx context.Context, fn func(tx *Tx) error, opts ...CallOption) error {
+	tx, err := c.BeginTx(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(ctx, opts...); rbErr != nil {
+			return stderrors.Join(err, rbErr)
+		}
+		return err
+	}
+	if err := tx.Commit(ctx, opts...); err != nil {
+		if rbErr := tx.Rollback(ctx, opts...); rbErr != nil && !stderrors.Is(rbErr, errors.ErrTxDone) {
+			return stderrors.Join(err, rbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Server-side transaction support, as learned by BeginTx
+const (
+	txUnknown int32 = iota
+	txSupported
+	txUnsupported
+)
+
+// txEndpointMissing reports whether err shows the server has no transaction
+// endpoint
+func txEndpointMissing(err error) bool {
+	var respErr *errors.NenDBResponseError
+	if !stderrors.As(err, &respErr) {
+		return false
+	}
+	return respErr.StatusCode == http.StatusMethodNotAllowed ||
+		(respErr.StatusCode == http.StatusNotFound && respErr.Code != "TX_NOT_FOUND")
+}
+
+// ID returns the server-side transaction ID, or an empty string for a
+// client-side transaction
+func (tx *Tx) ID() string {
+	return tx.id
+}
+
+// context tags ctx with the server-side transaction ID, if any
+func (tx *Tx) context(ctx context.Context) context.Context {
+	if tx.id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, txKey{}, tx.id)
+}
+
+// begin locks tx for a write, failing once the transaction has ended
+func (tx *Tx) begin() error {
+	tx.mu.Lock()
+	if tx.done {
+		tx.mu.Unlock()
+		return errors.ErrTxDone
+	}
+	return nil
+}
+
+// CreateNode creates a node within the transaction
+func (tx *Tx) CreateNode(ctx context.Context, labels []string, properties map[string]interface{}, opts ...CallOption) (*types.GraphNode, error) {
+	if err := tx.begin(); err != nil {
+		return nil, err
+	}
+	defer tx.mu.Unlock()
+
+	node, err := tx.client.CreateNode(tx.context(ctx), labels, properties, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if tx.id == "" {
+		tx.undo = append(tx.undo, func(ctx context.Context) error {
+			return alreadyDeleted(tx.client.DeleteNode(ctx, node.ID))
+		})
+	}
+	return node, nil
+}
+
+// UpdateNode updates a node within the transaction. Client-side transactions
+// fetch the node first so Rollback can restore it.
+func (tx *Tx) UpdateNode(ctx context.Context, nodeID int, labels []string, properties map[string]interface{}, opts ...CallOption) (*types.GraphNode, error) {
+	if err := tx.begin(); err != nil {
+		return nil, err
+	}
+	defer tx.mu.Unlock()
+
+	if tx.id != "" {
+		return tx.client.UpdateNode(tx.context(ctx), nodeID, labels, properties, opts...)
+	}
+	prev, err := tx.client.GetNode(ctx, nodeID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	node, err := tx.client.UpdateNode(ctx, nodeID, labels, properties, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tx.undo = append(tx.undo, func(ctx context.Context) error {
+		_, err := tx.client.UpdateNode(ctx, prev.ID, restoredLabels(prev.Labels), restoredProperties(prev.Properties))
+		return err
+	})
+	return node, nil
+}
+
+// DeleteNode deletes a node within the transaction. Client-side transactions
+// defer the delete until Commit.
+func (tx *Tx) DeleteNode(ctx context.Context, nodeID int, opts ...CallOption) error {
+	if err := tx.begin(); err != nil {
+		return err
+	}
+	defer tx.mu.Unlock()
+
+	if tx.id != "" {
+		return tx.client.DeleteNode(tx.context(ctx), nodeID, opts...)
+	}
+	tx.deletes = append(tx.deletes, func(ctx context.Context) error {
+		return tx.client.DeleteNode(ctx, nodeID, opts...)
+	})
+	return nil
+}
+
+// CreateEdge creates an edge within the transaction
+func (tx *Tx) CreateEdge(ctx context.Context, source, target int, edgeType string, properties map[string]interface{}, opts ...CallOption) (*types.GraphEdge, error) {
+	if err := tx.begin(); err != nil {
+		return nil, err
+	}
+	defer tx.mu.Unlock()
+
+	edge, err := tx.client.CreateEdge(tx.context(ctx), source, target, edgeType, properties, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if tx.id == "" {
+		tx.undo = append(tx.undo, func(ctx context.Context) error {
+			return alreadyDeleted(tx.client.DeleteEdge(ctx, edge.ID))
+		})
+	}
+	return edge, nil
+}
+
+// UpdateEdge updates an edge within the transaction. Client-side transactions
+// fetch the edge first so Rollback can restore it.
+func (tx *Tx) UpdateEdge(ctx context.Context, edgeID int, edgeType string, properties map[string]interface{}, opts ...CallOption) (*types.GraphEdge, error) {
+	if err := tx.begin(); err != nil {
+		return nil, err
+	}
+	defer tx.mu.Unlock()
+
+	if tx.id != "" {
+		return tx.client.UpdateEdge(tx.context(ctx), edgeID, edgeType, properties, opts...)
+	}
+	prev, err := tx.client.GetEdge(ctx, edgeID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	edge, err := tx.client.UpdateEdge(ctx, edgeID, edgeType, properties, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tx.undo = append(tx.undo, func(ctx context.Context) error {
+		_, err := tx.client.UpdateEdge(ctx, prev.ID, prev.Type, restoredProperties(prev.Properties))
+		return err
+	})
+	return edge, nil
+}
+
+// DeleteEdge deletes an edge within the transaction. Client-side transactions
+// defer the delete until Commit.
+func (tx *Tx) DeleteEdge(ctx context.Context, edgeID int, opts ...CallOption) error {
+	if err := tx.begin(); err != nil {
+		return err
+	}
+	defer tx.mu.Unlock()
+
+	if tx.id != "" {
+		return tx.client.DeleteEdge(tx.context(ctx), edgeID, opts...)
+	}
+	tx.deletes = append(tx.deletes, func(ctx context.Context) error {
+		return tx.client.DeleteEdge(ctx, edgeID, opts...)
+	})
+	return nil
+}
+
+// Commit makes the transaction's writes permanent. If a deferred delete of a
+// client-side transaction fails, the writes made so far are rolled back and a
+// *errors.NenDBTransactionError is returned; deletes that already succeeded
+// cannot be undone. If a server-side commit fails, the transaction stays open
+// and should be rolled back.
+func (tx *Tx) Commit(ctx context.Context, opts ...CallOption) error {
+	if err := tx.begin(); err != nil {
+		return err
+	}
+	defer tx.mu.Unlock()
+
+	if tx.id != "" {
+		_, err := tx.client.makeRequest(ctx, op("CommitTx"), "POST", fmt.Sprintf("/transactions/%s/commit", tx.id), nil, nil, opts...)
+		tx.done = txEnded(err)
+		return err
+	}
+	tx.done = true
+
+	for i, del := range tx.deletes {
+		if err := del(ctx); err != nil {
+			txErr := errors.NewTransactionError("Transaction commit failed; changes rolled back", map[string]interface{}{
+				"error":           err.Error(),
+				"deletes_applied": i,
+			})
+			txErr.Err = err
+			if rbErr := tx.compensate(ctx); rbErr != nil {
+				txErr.Message = "Transaction commit failed and could not be fully rolled back"
+				txErr.Details["rollback_error"] = rbErr.Error()
+				txErr.Err = stderrors.Join(err, rbErr)
+			}
+			return txErr
+		}
+	}
+	return nil
+}
+
+// Rollback discards the transaction's writes. It returns errors.ErrTxDone if
+// the transaction was already committed or rolled back, so it is safe to
+// defer after BeginTx.
+func (tx *Tx) Rollback(ctx context.Context, opts ...CallOption) error {
+	if err := tx.begin(); err != nil {
+		return err
+	}
+	defer tx.mu.Unlock()
+
+	if tx.id != "" {
+		_, err := tx.client.makeRequest(ctx, op("RollbackTx"), "DELETE", "/transactions/"+tx.id, nil, nil, opts...)
+		tx.done = txEnded(err)
+		return err
+	}
+	tx.done = true
+	return tx.compensate(ctx)
+}
+
+// txEnded reports whether a server-side commit or rollback that returned err
+// has ended the transaction. A transaction the server no longer knows about
+// has ended too; after any other failure it may still be open, so the
+// transaction stays usable for a Rollback.
+func txEnded(err error) bool {
+	return err == nil || stderrors.Is(err, errors.ErrNotFound)
+}
+
+// compensate undoes client-side writes in reverse order, carrying on past
+// failures so as much as possible is restored
+func (tx *Tx) compensate(ctx context.Context) error {
+	var errs []error
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		if err := tx.undo[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	tx.undo, tx.deletes = nil, nil
+	if len(errs) == 0 {
+		return nil
+	}
+
+	txErr := errors.NewTransactionError(
+		fmt.Sprintf("Failed to roll back %d of the transaction's writes", len(errs)),
+		map[string]interface{}{"failed": len(errs), "error": errs[0].Error()},
+	)
+	txErr.Err = stderrors.Join(errs...)
+	return txErr
+}
+
+// alreadyDeleted treats a node or edge that is already gone, for example
+// because the transaction itself deleted it, as successfully undone
+func alreadyDeleted(err error) error {
+	if stderrors.Is(err, errors.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// restoredLabels returns labels suitable for replacing a node's labels; a nil
+// slice would leave them unchanged
+func restoredLabels(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
+// restoredProperties returns properties suitable for replacing a node's or
+// edge's properties
+func restoredProperties(properties map[string]interface{}) map[string]interface{} {
+	if properties == nil {
+		return map[string]interface{}{}
+	}
+	return properties
+}
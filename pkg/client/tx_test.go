@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func TestServerTxCommitAndRollback(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+	existing := srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice"})
+
+	tx, err := client.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if tx.ID() == "" {
+		t.Fatal("Expected a server-side transaction")
+	}
+	node, err := tx.CreateNode(ctx, []string{"Person"}, map[string]interface{}{"name": "Bob"})
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if _, err := tx.UpdateNode(ctx, existing.ID, nil, map[string]interface{}{"name": "Alicia"}); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if srv.Node(node.ID) != nil {
+		t.Error("Expected created node to be rolled back")
+	}
+	if name := srv.Node(existing.ID).Properties["name"]; name != "Alice" {
+		t.Errorf("Expected update to be rolled back, got name %v", name)
+	}
+	if srv.Transactions() != 0 {
+		t.Errorf("Expected no open transactions, got %d", srv.Transactions())
+	}
+
+	err = client.WithTx(ctx, func(tx *Tx) error {
+		_, err := tx.CreateNode(ctx, []string{"Person"}, map[string]interface{}{"name": "Carol"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if len(srv.Nodes()) != 2 {
+		t.Errorf("Expected committed node to remain, got %d nodes", len(srv.Nodes()))
+	}
+}
+
+func TestServerTxRollbackAfterFailedCommit(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	tx, err := client.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	node, err := tx.CreateNode(ctx, []string{"Person"}, nil)
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	srv.FailNext(1, http.StatusInternalServerError)
+	if err := tx.Commit(ctx); err == nil {
+		t.Fatal("Expected the injected commit failure")
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Expected Rollback after a failed commit to succeed, got %v", err)
+	}
+	if srv.Node(node.ID) != nil || srv.Transactions() != 0 {
+		t.Error("Expected the transaction to be rolled back")
+	}
+	if err := tx.Rollback(ctx); !stderrors.Is(err, errors.ErrTxDone) {
+		t.Errorf("Expected ErrTxDone after Rollback, got %v", err)
+	}
+
+	err = client.WithTx(ctx, func(tx *Tx) error {
+		if _, err := tx.CreateNode(ctx, []string{"Person"}, nil); err != nil {
+			return err
+		}
+		srv.FailNext(1, http.StatusInternalServerError)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Expected WithTx to return the commit failure")
+	}
+	if len(srv.Nodes()) != 0 || srv.Transactions() != 0 {
+		t.Errorf("Expected WithTx to roll back after the failed commit, got %d nodes and %d transactions", len(srv.Nodes()), srv.Transactions())
+	}
+}
+
+func TestClientSideTxRollback(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	srv.SetTransactions(false)
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+	a := srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice"})
+	b := srv.AddNode([]string{"Person"}, nil)
+	edge, _ := srv.AddEdge(a.ID, b.ID, "KNOWS", map[string]interface{}{"since": 2020})
+
+	tx, err := client.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if tx.ID() != "" {
+		t.Fatalf("Expected a client-side transaction, got ID %q", tx.ID())
+	}
+	c, err := tx.CreateNode(ctx, []string{"Person"}, nil)
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if _, err := tx.CreateEdge(ctx, a.ID, c.ID, "KNOWS", nil); err != nil {
+		t.Fatalf("CreateEdge failed: %v", err)
+	}
+	if _, err := tx.UpdateNode(ctx, a.ID, []string{"Employee"}, map[string]interface{}{"name": "Alicia"}); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if _, err := tx.UpdateEdge(ctx, edge.ID, "LIKES", nil); err != nil {
+		t.Fatalf("UpdateEdge failed: %v", err)
+	}
+	if err := tx.DeleteNode(ctx, b.ID); err != nil {
+		t.Fatalf("DeleteNode failed: %v", err)
+	}
+	if srv.Node(b.ID) == nil {
+		t.Fatal("Expected client-side delete to be deferred until Commit")
+	}
+
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if len(srv.Nodes()) != 2 || len(srv.Edges()) != 1 {
+		t.Errorf("Expected 2 nodes and 1 edge after rollback, got %d and %d", len(srv.Nodes()), len(srv.Edges()))
+	}
+	restored := srv.Node(a.ID)
+	if restored.Labels[0] != "Person" || restored.Properties["name"] != "Alice" {
+		t.Errorf("Expected node to be restored, got %+v", restored)
+	}
+	if restoredEdge := srv.Edge(edge.ID); restoredEdge.Type != "KNOWS" {
+		t.Errorf("Expected edge type to be restored, got %q", restoredEdge.Type)
+	}
+
+	if err := tx.Commit(ctx); !stderrors.Is(err, errors.ErrTxDone) {
+		t.Errorf("Expected ErrTxDone after rollback, got %v", err)
+	}
+	if _, err := tx.CreateNode(ctx, nil, nil); !stderrors.Is(err, errors.ErrTxDone) {
+		t.Errorf("Expected ErrTxDone after rollback, got %v", err)
+	}
+}
+
+func TestClientSideTxCommit(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	srv.SetTransactions(false)
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+	a := srv.AddNode([]string{"Person"}, nil)
+
+	tx, err := client.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if _, err := tx.CreateNode(ctx, []string{"Person"}, nil); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if err := tx.DeleteNode(ctx, a.ID); err != nil {
+		t.Fatalf("DeleteNode failed: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if srv.Node(a.ID) != nil || len(srv.Nodes()) != 1 {
+		t.Errorf("Expected delete and create to be committed, got %d nodes", len(srv.Nodes()))
+	}
+	if err := tx.Rollback(ctx); !stderrors.Is(err, errors.ErrTxDone) {
+		t.Errorf("Expected ErrTxDone after commit, got %v", err)
+	}
+}
+
+func TestClientSideTxCommitFailureCompensates(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	srv.SetTransactions(false)
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	tx, err := client.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if _, err := tx.CreateNode(ctx, []string{"Person"}, nil); err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if err := tx.DeleteEdge(ctx, 999); err != nil {
+		t.Fatalf("DeleteEdge failed: %v", err)
+	}
+
+	err = tx.Commit(ctx)
+	var txErr *errors.NenDBTransactionError
+	if !stderrors.As(err, &txErr) {
+		t.Fatalf("Expected *errors.NenDBTransactionError, got %T: %v", err, err)
+	}
+	if !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("Expected commit error to wrap the failed delete, got %v", err)
+	}
+	if len(srv.Nodes()) != 0 {
+		t.Errorf("Expected created node to be compensated, got %d nodes", len(srv.Nodes()))
+	}
+}
+
+func TestClientSideTxCompensatesDeletedCreates(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	srv.SetTransactions(false)
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	tx, err := client.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	node, err := tx.CreateNode(ctx, []string{"Person"}, nil)
+	if err != nil {
+		t.Fatalf("CreateNode failed: %v", err)
+	}
+	if err := tx.DeleteNode(ctx, node.ID); err != nil {
+		t.Fatalf("DeleteNode failed: %v", err)
+	}
+	if err := tx.DeleteEdge(ctx, 999); err != nil {
+		t.Fatalf("DeleteEdge failed: %v", err)
+	}
+
+	// The node is deleted by Commit before the edge delete fails, so undoing
+	// its creation finds nothing left to delete
+	err = tx.Commit(ctx)
+	var txErr *errors.NenDBTransactionError
+	if !stderrors.As(err, &txErr) || txErr.Message != "Transaction commit failed; changes rolled back" {
+		t.Errorf("Expected the rollback to succeed, got %v", err)
+	}
+	if len(srv.Nodes()) != 0 {
+		t.Errorf("Expected no nodes, got %d", len(srv.Nodes()))
+	}
+}
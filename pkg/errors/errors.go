@@ -1,15 +1,44 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
+	"net/http"
 	"time"
 )
 
+// Sentinel errors for classifying failures with errors.Is:
+//
+//	if errors.Is(err, nendberrors.ErrNotFound) { ... }
+var (
+	ErrNotFound     = stderrors.New("nendb: not found")
+	ErrConflict     = stderrors.New("nendb: conflict")
+	ErrUnauthorized = stderrors.New("nendb: unauthorized")
+	ErrRateLimited  = stderrors.New("nendb: rate limited")
+
+	// ErrTxDone is returned by operations on a transaction that has already
+	// been committed or rolled back
+	ErrTxDone = stderrors.New("nendb: transaction has already been committed or rolled back")
+)
+
 // NenDBError represents the base error type for NenDB operations
 type NenDBError struct {
 	Message string                 `json:"message"`
 	Details map[string]interface{} `json:"details,omitempty"`
-	Time    time.Time             `json:"time"`
+	Time    time.Time              `json:"time"`
+
+	// StatusCode is the HTTP status of the response, or 0 if none was received
+	StatusCode int `json:"status_code,omitempty"`
+	// Code is the machine-readable error code sent by the server
+	Code string `json:"code,omitempty"`
+	// RequestID identifies the request in server logs, when the server sent one
+	RequestID string `json:"request_id,omitempty"`
+	// Method and Endpoint describe the request that failed
+	Method   string `json:"method,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Err is the underlying cause, such as a *net.OpError
+	Err error `json:"-"`
 }
 
 func (e *NenDBError) Error() string {
@@ -19,6 +48,39 @@ func (e *NenDBError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying cause
+func (e *NenDBError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches one of the sentinel errors, based on
+// the HTTP status code or, when there is none, the server error code
+func (e *NenDBError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case 0:
+		return codeSentinels[e.Code] == target && target != nil
+	}
+	return false
+}
+
+// codeSentinels maps server error codes to sentinels for errors that carry no
+// HTTP status
+var codeSentinels = map[string]error{
+	"NOT_FOUND":    ErrNotFound,
+	"CONFLICT":     ErrConflict,
+	"UNAUTHORIZED": ErrUnauthorized,
+	"FORBIDDEN":    ErrUnauthorized,
+	"RATE_LIMITED": ErrRateLimited,
+}
+
 // New creates a new NenDBError
 func New(message string, details map[string]interface{}) *NenDBError {
 	if details == nil {
@@ -85,3 +147,88 @@ func NewResponseError(message string, details map[string]interface{}) *NenDBResp
 		NenDBError: New(message, details),
 	}
 }
+
+// NewStatusError creates a NenDBResponseError for a non-2xx HTTP response
+func NewStatusError(statusCode int, code, message string, details map[string]interface{}) *NenDBResponseError {
+	err := NewResponseError(message, details)
+	err.StatusCode = statusCode
+	err.Code = code
+	return err
+}
+
+// NenDBTransactionError is raised when a transaction cannot be committed or
+// fully rolled back
+type NenDBTransactionError struct {
+	*NenDBError
+}
+
+func NewTransactionError(message string, details map[string]interface{}) *NenDBTransactionError {
+	return &NenDBTransactionError{
+		NenDBError: New(message, details),
+	}
+}
+
+// NenDBCircuitOpenError is raised without contacting the server while the
+// client's circuit breaker is open
+type NenDBCircuitOpenError struct {
+	*NenDBError
+	// RetryAfter is how long the breaker stays open; zero while it is
+	// half-open and its probe requests are already in flight
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+func NewCircuitOpenError(message string, details map[string]interface{}) *NenDBCircuitOpenError {
+	return &NenDBCircuitOpenError{
+		NenDBError: New(message, details),
+	}
+}
+
+// BatchFailure describes a single item that failed within a batch operation
+type BatchFailure struct {
+	Index   int    `json:"index"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// NenDBBatchError is raised when some items of a batch operation fail
+type NenDBBatchError struct {
+	*NenDBError
+	Failures []BatchFailure `json:"failures"`
+}
+
+func NewBatchError(message string, failures []BatchFailure) *NenDBBatchError {
+	return &NenDBBatchError{
+		NenDBError: New(message, map[string]interface{}{"failed": len(failures)}),
+		Failures:   failures,
+	}
+}
+
+// TypeName returns the name of the first NenDB error type in err's chain,
+// such as "NenDBTimeoutError", or an empty string when there is none. It
+// gives metrics and logs a low-cardinality error classification.
+func TypeName(err error) string {
+	for err != nil {
+		switch err.(type) {
+		case *NenDBConnectionError:
+			return "NenDBConnectionError"
+		case *NenDBTimeoutError:
+			return "NenDBTimeoutError"
+		case *NenDBValidationError:
+			return "NenDBValidationError"
+		case *NenDBAlgorithmError:
+			return "NenDBAlgorithmError"
+		case *NenDBResponseError:
+			return "NenDBResponseError"
+		case *NenDBTransactionError:
+			return "NenDBTransactionError"
+		case *NenDBBatchError:
+			return "NenDBBatchError"
+		case *NenDBCircuitOpenError:
+			return "NenDBCircuitOpenError"
+		case *NenDBError:
+			return "NenDBError"
+		}
+		err = stderrors.Unwrap(err)
+	}
+	return ""
+}
@@ -1,6 +1,9 @@
 package errors
 
 import (
+	stderrors "errors"
+	"fmt"
+	"net"
 	"testing"
 )
 
@@ -136,3 +139,94 @@ func TestErrorInheritance(t *testing.T) {
 		t.Error("ResponseError should inherit Message from NenDBError")
 	}
 }
+
+func TestNenDBBatchError(t *testing.T) {
+	failures := []BatchFailure{
+		{Index: 1, Code: "NOT_FOUND", Message: "source node 9 not found"},
+		{Index: 4, Message: "invalid properties"},
+	}
+	err := NewBatchError("2 of 5 items failed", failures)
+
+	if err.Message != "2 of 5 items failed" {
+		t.Errorf("Expected message '2 of 5 items failed', got '%s'", err.Message)
+	}
+	if err.Details["failed"] != 2 {
+		t.Errorf("Expected details failed 2, got '%v'", err.Details["failed"])
+	}
+	if len(err.Failures) != 2 || err.Failures[1].Index != 4 {
+		t.Errorf("Expected failures to be preserved, got %v", err.Failures)
+	}
+}
+
+func TestStatusErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		code   string
+		want   error
+	}{
+		{404, "NOT_FOUND", ErrNotFound},
+		{409, "", ErrConflict},
+		{401, "", ErrUnauthorized},
+		{403, "", ErrUnauthorized},
+		{429, "", ErrRateLimited},
+		{0, "NOT_FOUND", ErrNotFound},
+	}
+	sentinels := []error{ErrNotFound, ErrConflict, ErrUnauthorized, ErrRateLimited}
+
+	for _, tt := range tests {
+		err := NewStatusError(tt.status, tt.code, "failed", nil)
+		for _, s := range sentinels {
+			if got := stderrors.Is(err, s); got != (s == tt.want) {
+				t.Errorf("status %d code %q: errors.Is(err, %v) = %v", tt.status, tt.code, s, got)
+			}
+		}
+	}
+
+	if stderrors.Is(NewStatusError(500, "INTERNAL_ERROR", "failed", nil), ErrNotFound) {
+		t.Error("Expected 500 not to match ErrNotFound")
+	}
+	if stderrors.Is(NewResponseError("failed", nil), ErrNotFound) {
+		t.Error("Expected error without status or code not to match ErrNotFound")
+	}
+}
+
+func TestErrorUnwrap(t *testing.T) {
+	cause := &net.OpError{Op: "dial", Net: "tcp", Err: stderrors.New("connection refused")}
+	err := NewConnectionError("Request failed", nil)
+	err.Err = cause
+
+	var opErr *net.OpError
+	if !stderrors.As(err, &opErr) || opErr != cause {
+		t.Error("Expected errors.As to find the underlying *net.OpError")
+	}
+
+	var connErr *NenDBConnectionError
+	var wrapped error = err
+	if !stderrors.As(wrapped, &connErr) {
+		t.Error("Expected errors.As to find *NenDBConnectionError")
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	txErr := NewTransactionError("commit failed", nil)
+	txErr.Err = NewStatusError(500, "", "boom", nil)
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{NewTimeoutError("slow", nil), "NenDBTimeoutError"},
+		{fmt.Errorf("get node: %w", NewStatusError(404, "NOT_FOUND", "missing", nil)), "NenDBResponseError"},
+		{txErr, "NenDBTransactionError"},
+		{NewBatchError("failed", nil), "NenDBBatchError"},
+		{NewCircuitOpenError("open", nil), "NenDBCircuitOpenError"},
+		{New("plain", nil), "NenDBError"},
+		{stderrors.New("other"), ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := TypeName(tt.err); got != tt.want {
+			t.Errorf("TypeName(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package exporter reads a graph out of NenDB and writes it as GraphML, GEXF
+// or Graphviz DOT.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// Format identifies an export file format
+type Format string
+
+const (
+	FormatGraphML Format = "graphml"
+	FormatGEXF    Format = "gexf"
+	FormatDOT     Format = "dot"
+)
+
+// FormatFromPath infers the export format from a file extension
+func FormatFromPath(path string) (Format, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".graphml"):
+		return FormatGraphML, nil
+	case strings.HasSuffix(lower, ".gexf"):
+		return FormatGEXF, nil
+	case strings.HasSuffix(lower, ".dot"), strings.HasSuffix(lower, ".gv"):
+		return FormatDOT, nil
+	default:
+		return "", fmt.Errorf("cannot infer format of %s (expected .graphml, .gexf, .dot or .gv)", path)
+	}
+}
+
+// pageSize is the number of nodes or edges fetched per request
+const pageSize = 1000
+
+// Graph is a set of nodes and the edges between them
+type Graph struct {
+	Nodes []types.GraphNode
+	Edges []types.GraphEdge
+}
+
+// Options selects which part of the graph is exported
+type Options struct {
+	// Label restricts the export to nodes carrying this label
+	Label string
+	// EdgeType restricts the export to edges of this type
+	EdgeType string
+}
+
+// Fetch reads the nodes and edges selected by opts from the server, paging
+// through the list endpoints. Edges whose endpoints were not selected are
+// dropped.
+func Fetch(ctx context.Context, c *client.NenDBClient, opts Options) (*Graph, error) {
+	g := &Graph{}
+	selected := make(map[int]bool)
+	for n, err := range c.Nodes(ctx, &client.NodeListOptions{Label: opts.Label, Limit: pageSize}) {
+		if err != nil {
+			return nil, err
+		}
+		g.Nodes = append(g.Nodes, *n)
+		selected[n.ID] = true
+	}
+
+	for e, err := range c.Edges(ctx, &client.EdgeListOptions{Type: opts.EdgeType, Limit: pageSize}) {
+		if err != nil {
+			return nil, err
+		}
+		if selected[e.Source] && selected[e.Target] {
+			g.Edges = append(g.Edges, *e)
+		}
+	}
+
+	return g, nil
+}
+
+// Write encodes g to w in the given format
+func Write(w io.Writer, g *Graph, format Format) error {
+	switch format {
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	case FormatGEXF:
+		return WriteGEXF(w, g)
+	case FormatDOT:
+		return WriteDOT(w, g)
+	default:
+		return errors.NewValidationError("Unsupported export format", map[string]interface{}{"format": string(format)})
+	}
+}
+
+// Export fetches the graph selected by opts and writes it to w
+func Export(ctx context.Context, c *client.NenDBClient, w io.Writer, format Format, opts Options) (*Graph, error) {
+	g, err := Fetch(ctx, c, opts)
+	if err != nil {
+		return nil, err
+	}
+	return g, Write(w, g, format)
+}
@@ -0,0 +1,169 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+func sampleGraph() *Graph {
+	return &Graph{
+		Nodes: []types.GraphNode{
+			{ID: 1, Labels: []string{"Person"}, Properties: map[string]interface{}{"name": "Alice & Co", "age": float64(30)}},
+			{ID: 2, Labels: []string{"Person", "Admin"}, Properties: map[string]interface{}{"name": `Bob "B"`, "age": 25.5, "active": true}},
+		},
+		Edges: []types.GraphEdge{
+			{ID: 7, Source: 1, Target: 2, Type: "KNOWS", Properties: map[string]interface{}{"since": "2022"}},
+		},
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, sampleGraph()); err != nil {
+		t.Fatalf("Failed to write GraphML: %v", err)
+	}
+
+	var doc struct {
+		Keys []struct {
+			Name string `xml:"attr.name,attr"`
+			Type string `xml:"attr.type,attr"`
+		} `xml:"key"`
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected well-formed GraphML, got %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 {
+		t.Errorf("Expected 2 nodes and 1 edge, got %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if doc.Graph.Edges[0].Source != "n1" || doc.Graph.Edges[0].Target != "n2" {
+		t.Errorf("Expected edge n1->n2, got %+v", doc.Graph.Edges[0])
+	}
+
+	keyTypes := map[string]string{}
+	for _, k := range doc.Keys {
+		keyTypes[k.Name] = k.Type
+	}
+	if keyTypes["age"] != "double" || keyTypes["active"] != "boolean" || keyTypes["name"] != "string" {
+		t.Errorf("Expected inferred key types, got %v", keyTypes)
+	}
+}
+
+func TestWriteGEXF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGEXF(&buf, sampleGraph()); err != nil {
+		t.Fatalf("Failed to write GEXF: %v", err)
+	}
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				Label string `xml:"label,attr"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				Label string `xml:"label,attr"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected well-formed GEXF, got %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != 2 || doc.Graph.Nodes[1].Label != "Person:Admin" {
+		t.Errorf("Expected node labels to be preserved, got %+v", doc.Graph.Nodes)
+	}
+	if len(doc.Graph.Edges) != 1 || doc.Graph.Edges[0].Label != "KNOWS" {
+		t.Errorf("Expected edge type as label, got %+v", doc.Graph.Edges)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, sampleGraph()); err != nil {
+		t.Fatalf("Failed to write DOT: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"digraph nendb {",
+		`  1 [label="Person", "age"="30", "name"="Alice & Co"];`,
+		`"name"="Bob \"B\""`,
+		`  1 -> 2 [label="KNOWS", "since"="2022"];`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected DOT output to contain %q, got:\n%s", e, out)
+		}
+	}
+}
+
+func TestExportFromServer(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	a := srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice"})
+	b := srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Bob"})
+	c := srv.AddNode([]string{"Company"}, map[string]interface{}{"name": "Nen"})
+	srv.AddEdge(a.ID, b.ID, "KNOWS", nil)
+	srv.AddEdge(a.ID, c.ID, "WORKS_AT", nil)
+
+	config := client.DefaultConfig()
+	config.BaseURL = srv.URL
+	nc, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	g, err := Fetch(context.Background(), nc, Options{})
+	if err != nil {
+		t.Fatalf("Failed to fetch graph: %v", err)
+	}
+	if len(g.Nodes) != 3 || len(g.Edges) != 2 {
+		t.Errorf("Expected 3 nodes and 2 edges, got %d and %d", len(g.Nodes), len(g.Edges))
+	}
+
+	// Filtering by label drops edges leaving the selection
+	var buf bytes.Buffer
+	g, err = Export(context.Background(), nc, &buf, FormatDOT, Options{Label: "Person"})
+	if err != nil {
+		t.Fatalf("Failed to export graph: %v", err)
+	}
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 {
+		t.Errorf("Expected 2 nodes and 1 edge, got %d and %d", len(g.Nodes), len(g.Edges))
+	}
+	if !strings.Contains(buf.String(), "KNOWS") || strings.Contains(buf.String(), "WORKS_AT") {
+		t.Errorf("Expected only KNOWS edge in output, got:\n%s", buf.String())
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"graph.graphml": FormatGraphML,
+		"graph.gexf":    FormatGEXF,
+		"graph.dot":     FormatDOT,
+		"graph.gv":      FormatDOT,
+	}
+	for path, want := range tests {
+		got, err := FormatFromPath(path)
+		if err != nil || got != want {
+			t.Errorf("Expected %s for %s, got %s (%v)", want, path, got, err)
+		}
+	}
+	if _, err := FormatFromPath("graph.json"); err == nil {
+		t.Error("Expected error for unknown extension, got nil")
+	}
+}
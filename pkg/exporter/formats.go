@@ -0,0 +1,300 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// attrType is the inferred type of a property column
+type attrType int
+
+const (
+	attrBoolean attrType = iota
+	attrLong
+	attrDouble
+	attrString
+)
+
+// attribute describes a property key shared by nodes or edges
+type attribute struct {
+	id   string
+	name string
+	typ  attrType
+}
+
+// collectAttributes returns the sorted property keys of a set of property maps
+// with the narrowest type able to hold every value
+func collectAttributes(prefix string, props []map[string]interface{}) []attribute {
+	types := make(map[string]attrType)
+	for _, p := range props {
+		for k, v := range p {
+			t := valueType(v)
+			if old, ok := types[k]; ok {
+				t = widen(old, t)
+			}
+			types[k] = t
+		}
+	}
+
+	names := make([]string, 0, len(types))
+	for k := range types {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	attrs := make([]attribute, len(names))
+	for i, name := range names {
+		attrs[i] = attribute{id: fmt.Sprintf("%s%d", prefix, i), name: name, typ: types[name]}
+	}
+	return attrs
+}
+
+func valueType(v interface{}) attrType {
+	switch val := v.(type) {
+	case bool:
+		return attrBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return attrLong
+	case float32:
+		return attrDouble
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return attrLong
+		}
+		return attrDouble
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return attrLong
+		}
+		return attrDouble
+	default:
+		return attrString
+	}
+}
+
+func widen(a, b attrType) attrType {
+	if a == b {
+		return a
+	}
+	if (a == attrLong || a == attrDouble) && (b == attrLong || b == attrDouble) {
+		return attrDouble
+	}
+	return attrString
+}
+
+// formatValue renders a property value as text
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, json.Number:
+		return fmt.Sprint(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case time.Duration:
+		return val.String()
+	case []byte:
+		return base64.StdEncoding.EncodeToString(val)
+	default:
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(raw)
+	}
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func nodeProperties(g *Graph) []map[string]interface{} {
+	props := make([]map[string]interface{}, len(g.Nodes))
+	for i, n := range g.Nodes {
+		props[i] = n.Properties
+	}
+	return props
+}
+
+func edgeProperties(g *Graph) []map[string]interface{} {
+	props := make([]map[string]interface{}, len(g.Edges))
+	for i, e := range g.Edges {
+		props[i] = e.Properties
+	}
+	return props
+}
+
+// attrTypeNames are the type names shared by GraphML and GEXF
+var attrTypeNames = map[attrType]string{
+	attrBoolean: "boolean",
+	attrLong:    "long",
+	attrDouble:  "double",
+	attrString:  "string",
+}
+
+// WriteGraphML writes g as a GraphML document. Node labels are stored in the
+// "labels" key joined by ':', edge types in the "type" key.
+func WriteGraphML(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	nodeAttrs := collectAttributes("n", nodeProperties(g))
+	edgeAttrs := collectAttributes("e", edgeProperties(g))
+
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(bw, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">`)
+	fmt.Fprintln(bw, `  <key id="labels" for="node" attr.name="labels" attr.type="string"/>`)
+	fmt.Fprintln(bw, `  <key id="type" for="edge" attr.name="type" attr.type="string"/>`)
+	for _, a := range nodeAttrs {
+		fmt.Fprintf(bw, "  <key id=\"%s\" for=\"node\" attr.name=\"%s\" attr.type=\"%s\"/>\n", a.id, escapeXML(a.name), attrTypeNames[a.typ])
+	}
+	for _, a := range edgeAttrs {
+		fmt.Fprintf(bw, "  <key id=\"%s\" for=\"edge\" attr.name=\"%s\" attr.type=\"%s\"/>\n", a.id, escapeXML(a.name), attrTypeNames[a.typ])
+	}
+
+	fmt.Fprintln(bw, `  <graph id="nendb" edgedefault="directed">`)
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "    <node id=\"n%d\">\n", n.ID)
+		fmt.Fprintf(bw, "      <data key=\"labels\">%s</data>\n", escapeXML(strings.Join(n.Labels, ":")))
+		for _, a := range nodeAttrs {
+			if v, ok := n.Properties[a.name]; ok {
+				fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", a.id, escapeXML(formatValue(v)))
+			}
+		}
+		fmt.Fprintln(bw, "    </node>")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"n%d\" target=\"n%d\">\n", e.ID, e.Source, e.Target)
+		fmt.Fprintf(bw, "      <data key=\"type\">%s</data>\n", escapeXML(e.Type))
+		for _, a := range edgeAttrs {
+			if v, ok := e.Properties[a.name]; ok {
+				fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", a.id, escapeXML(formatValue(v)))
+			}
+		}
+		fmt.Fprintln(bw, "    </edge>")
+	}
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</graphml>")
+	return bw.Flush()
+}
+
+// WriteGEXF writes g as a GEXF 1.3 document. Node labels become the GEXF
+// node label joined by ':' and edge types the GEXF edge label.
+func WriteGEXF(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	nodeAttrs := collectAttributes("", nodeProperties(g))
+	edgeAttrs := collectAttributes("", edgeProperties(g))
+
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(bw, `<gexf xmlns="http://gexf.net/1.3" version="1.3">`)
+	fmt.Fprintln(bw, `  <meta>`)
+	fmt.Fprintln(bw, `    <creator>nendb-go</creator>`)
+	fmt.Fprintln(bw, `  </meta>`)
+	fmt.Fprintln(bw, `  <graph mode="static" defaultedgetype="directed">`)
+	writeGEXFAttributes(bw, "node", nodeAttrs)
+	writeGEXFAttributes(bw, "edge", edgeAttrs)
+
+	fmt.Fprintln(bw, "    <nodes>")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "      <node id=\"%d\" label=\"%s\">\n", n.ID, escapeXML(strings.Join(n.Labels, ":")))
+		writeGEXFValues(bw, nodeAttrs, n.Properties)
+		fmt.Fprintln(bw, "      </node>")
+	}
+	fmt.Fprintln(bw, "    </nodes>")
+
+	fmt.Fprintln(bw, "    <edges>")
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "      <edge id=\"%d\" source=\"%d\" target=\"%d\" label=\"%s\">\n", e.ID, e.Source, e.Target, escapeXML(e.Type))
+		writeGEXFValues(bw, edgeAttrs, e.Properties)
+		fmt.Fprintln(bw, "      </edge>")
+	}
+	fmt.Fprintln(bw, "    </edges>")
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</gexf>")
+	return bw.Flush()
+}
+
+func writeGEXFAttributes(w io.Writer, class string, attrs []attribute) {
+	if len(attrs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "    <attributes class=\"%s\">\n", class)
+	for _, a := range attrs {
+		fmt.Fprintf(w, "      <attribute id=\"%s\" title=\"%s\" type=\"%s\"/>\n", a.id, escapeXML(a.name), attrTypeNames[a.typ])
+	}
+	fmt.Fprintln(w, "    </attributes>")
+}
+
+func writeGEXFValues(w io.Writer, attrs []attribute, props map[string]interface{}) {
+	var values []string
+	for _, a := range attrs {
+		if v, ok := props[a.name]; ok {
+			values = append(values, fmt.Sprintf("          <attvalue for=\"%s\" value=\"%s\"/>", a.id, escapeXML(formatValue(v))))
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "        <attvalues>")
+	for _, v := range values {
+		fmt.Fprintln(w, v)
+	}
+	fmt.Fprintln(w, "        </attvalues>")
+}
+
+// WriteDOT writes g as a Graphviz digraph. Node labels and edge types are
+// written as the "label" attribute; properties become additional attributes,
+// except for properties named "label", which are skipped.
+func WriteDOT(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph nendb {")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(strings.Join(n.Labels, ":"))}
+		attrs = append(attrs, dotAttributes(n.Properties)...)
+		fmt.Fprintf(bw, "  %d [%s];\n", n.ID, strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		attrs := []string{"label=" + dotQuote(e.Type)}
+		attrs = append(attrs, dotAttributes(e.Properties)...)
+		fmt.Fprintf(bw, "  %d -> %d [%s];\n", e.Source, e.Target, strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func dotAttributes(props map[string]interface{}) []string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		if k != "label" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	attrs := make([]string, len(keys))
+	for i, k := range keys {
+		attrs[i] = dotQuote(k) + "=" + dotQuote(formatValue(props[k]))
+	}
+	return attrs
+}
+
+// dotQuote returns s as a double-quoted DOT identifier
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,460 @@
+// Package importer streams nodes and edges from CSV or JSON Lines files into
+// NenDB using the client batch API.
+//
+// Nodes are imported first; each row may carry an external key which the
+// importer remembers along with the NenDB ID assigned to it. Edge rows then
+// refer to their endpoints by those external keys.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+const (
+	// DefaultConcurrency is the number of batches sent in parallel when
+	// Options.Concurrency is not set
+	DefaultConcurrency = 4
+
+	// DefaultLabelSeparator splits multiple labels held in a single column
+	DefaultLabelSeparator = ";"
+
+	// maxRecordedErrors caps the number of row errors kept in Stats.Errors
+	maxRecordedErrors = 1000
+)
+
+// Options controls how an Importer streams data
+type Options struct {
+	// BatchSize is the number of rows sent per request. Zero uses the
+	// client's configured batch size.
+	BatchSize int
+	// Concurrency is the maximum number of batches in flight
+	Concurrency int
+	// InferTypes converts CSV cells that look like booleans or numbers.
+	// JSON Lines values always keep their JSON types.
+	InferTypes bool
+	// LabelSeparator splits label columns holding several labels
+	LabelSeparator string
+	// Progress, if set, is called after every completed batch. Calls are
+	// serialized.
+	Progress func(Progress)
+}
+
+// Progress reports the state of a running import
+type Progress struct {
+	Kind    string // "nodes" or "edges"
+	Read    int
+	Created int
+	Failed  int
+}
+
+// NodeMapping maps input columns to node fields
+type NodeMapping struct {
+	// KeyColumn holds the external key used by edge files to refer to the
+	// node. Leave empty if edges will not reference these nodes.
+	KeyColumn string
+	// Labels are added to every node
+	Labels []string
+	// LabelColumn holds additional per-row labels
+	LabelColumn string
+	// Properties lists the columns stored as properties. Empty means every
+	// column except LabelColumn.
+	Properties []string
+}
+
+// EdgeMapping maps input columns to edge fields
+type EdgeMapping struct {
+	SourceColumn string
+	TargetColumn string
+	// Type is used for every edge unless TypeColumn is set and non-empty
+	Type       string
+	TypeColumn string
+	// Properties lists the columns stored as properties. Empty means every
+	// column except the source, target and type columns.
+	Properties []string
+	// ResolveNumericIDs treats source and target values that are not known
+	// keys as existing NenDB node IDs
+	ResolveNumericIDs bool
+}
+
+// RowError describes an input row that could not be imported
+type RowError struct {
+	Line    int
+	Message string
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Stats summarizes a completed import
+type Stats struct {
+	Read     int
+	Created  int
+	Failed   int
+	Errors   []RowError
+	Duration time.Duration
+}
+
+// Importer loads graph data into NenDB. It keeps the mapping from external
+// node keys to NenDB IDs across calls, so edges can be imported after nodes.
+type Importer struct {
+	client *client.NenDBClient
+	opts   Options
+
+	mu   sync.RWMutex
+	keys map[string]int
+}
+
+// New creates an Importer that writes through c
+func New(c *client.NenDBClient, opts Options) *Importer {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.LabelSeparator == "" {
+		opts.LabelSeparator = DefaultLabelSeparator
+	}
+	return &Importer{
+		client: c,
+		opts:   opts,
+		keys:   make(map[string]int),
+	}
+}
+
+// Resolve returns the NenDB ID assigned to an external node key
+func (im *Importer) Resolve(key string) (int, bool) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+	id, ok := im.keys[key]
+	return id, ok
+}
+
+// Keys returns the number of external node keys resolved so far
+func (im *Importer) Keys() int {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+	return len(im.keys)
+}
+
+// batch is a chunk of rows ready to be sent to the server
+type batch struct {
+	lines []int
+	keys  []string
+	nodes []types.GraphNode
+	edges []types.GraphEdge
+}
+
+func (b *batch) len() int {
+	return len(b.lines)
+}
+
+// ImportNodes streams nodes from r and records the IDs assigned to their keys
+func (im *Importer) ImportNodes(ctx context.Context, r io.Reader, format Format, m NodeMapping) (*Stats, error) {
+	rr, err := newRecordReader(r, format, im.opts.InferTypes)
+	if err != nil {
+		return nil, errors.NewValidationError("Failed to read node input", map[string]interface{}{"error": err.Error()})
+	}
+
+	exclude := map[string]bool{m.LabelColumn: true}
+	seen := make(map[string]bool)
+
+	build := func(rec *record, b *batch) error {
+		var key string
+		if m.KeyColumn != "" {
+			key = stringValue(rec.fields[m.KeyColumn])
+			if key == "" {
+				return fmt.Errorf("missing key column %q", m.KeyColumn)
+			}
+			if _, ok := im.Resolve(key); ok || seen[key] {
+				return fmt.Errorf("duplicate key %q", key)
+			}
+			seen[key] = true
+		}
+
+		labels := append([]string{}, m.Labels...)
+		if m.LabelColumn != "" {
+			labels = append(labels, im.splitLabels(rec.fields[m.LabelColumn])...)
+		}
+
+		props := selectProperties(rec.fields, m.Properties, exclude)
+		if err := types.ValidateProperties(props); err != nil {
+			return err
+		}
+
+		b.keys = append(b.keys, key)
+		b.nodes = append(b.nodes, types.GraphNode{
+			Labels:     labels,
+			Properties: props,
+		})
+		return nil
+	}
+
+	send := func(ctx context.Context, b *batch) ([]int, error) {
+		ids, err := im.client.CreateNodes(ctx, b.nodes)
+		im.mu.Lock()
+		for i, id := range ids {
+			if id != client.UnassignedID && b.keys[i] != "" {
+				im.keys[b.keys[i]] = id
+			}
+		}
+		im.mu.Unlock()
+		return ids, err
+	}
+
+	return im.run(ctx, "nodes", rr, build, send)
+}
+
+// ImportEdges streams edges from r, resolving endpoints through the keys
+// recorded by earlier ImportNodes calls
+func (im *Importer) ImportEdges(ctx context.Context, r io.Reader, format Format, m EdgeMapping) (*Stats, error) {
+	if m.SourceColumn == "" || m.TargetColumn == "" {
+		return nil, errors.NewValidationError("Edge mapping requires source and target columns", nil)
+	}
+	if m.Type == "" && m.TypeColumn == "" {
+		return nil, errors.NewValidationError("Edge mapping requires a type or type column", nil)
+	}
+
+	rr, err := newRecordReader(r, format, im.opts.InferTypes)
+	if err != nil {
+		return nil, errors.NewValidationError("Failed to read edge input", map[string]interface{}{"error": err.Error()})
+	}
+
+	exclude := map[string]bool{m.SourceColumn: true, m.TargetColumn: true, m.TypeColumn: true}
+
+	build := func(rec *record, b *batch) error {
+		source, err := im.resolveEndpoint(rec.fields[m.SourceColumn], m.ResolveNumericIDs)
+		if err != nil {
+			return fmt.Errorf("source: %v", err)
+		}
+		target, err := im.resolveEndpoint(rec.fields[m.TargetColumn], m.ResolveNumericIDs)
+		if err != nil {
+			return fmt.Errorf("target: %v", err)
+		}
+
+		edgeType := m.Type
+		if m.TypeColumn != "" {
+			if t := stringValue(rec.fields[m.TypeColumn]); t != "" {
+				edgeType = t
+			}
+		}
+		if edgeType == "" {
+			return fmt.Errorf("missing edge type")
+		}
+
+		props := selectProperties(rec.fields, m.Properties, exclude)
+		if err := types.ValidateProperties(props); err != nil {
+			return err
+		}
+
+		b.edges = append(b.edges, types.GraphEdge{
+			Source:     source,
+			Target:     target,
+			Type:       edgeType,
+			Properties: props,
+		})
+		return nil
+	}
+
+	send := func(ctx context.Context, b *batch) ([]int, error) {
+		return im.client.CreateEdges(ctx, b.edges)
+	}
+
+	return im.run(ctx, "edges", rr, build, send)
+}
+
+// run reads records, groups them into batches and sends them with bounded
+// concurrency. A request-level error cancels the import; per-row failures are
+// counted and recorded in the returned Stats.
+func (im *Importer) run(ctx context.Context, kind string, rr recordReader,
+	build func(*record, *batch) error, send func(context.Context, *batch) ([]int, error)) (*Stats, error) {
+
+	start := time.Now()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batchSize := im.opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = im.client.BatchSize()
+	}
+
+	stats := &Stats{}
+	var (
+		mu       sync.Mutex
+		fatalErr error
+		wg       sync.WaitGroup
+	)
+
+	recordError := func(line int, message string) {
+		stats.Failed++
+		if len(stats.Errors) < maxRecordedErrors {
+			stats.Errors = append(stats.Errors, RowError{Line: line, Message: message})
+		}
+	}
+	report := func() {
+		if im.opts.Progress != nil {
+			im.opts.Progress(Progress{Kind: kind, Read: stats.Read, Created: stats.Created, Failed: stats.Failed})
+		}
+	}
+
+	batches := make(chan *batch)
+	for i := 0; i < im.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				ids, err := send(ctx, b)
+
+				mu.Lock()
+				batchErr, partial := err.(*errors.NenDBBatchError)
+				if err != nil && !partial {
+					if fatalErr == nil {
+						fatalErr = err
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				for _, id := range ids {
+					if id != client.UnassignedID {
+						stats.Created++
+					}
+				}
+				if partial {
+					for _, f := range batchErr.Failures {
+						recordError(b.lines[f.Index], f.Message)
+					}
+				}
+				report()
+				mu.Unlock()
+			}
+		}()
+	}
+
+	pending := &batch{}
+	flush := func() bool {
+		if pending.len() == 0 {
+			return true
+		}
+		select {
+		case batches <- pending:
+			pending = &batch{}
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var readErr error
+	for {
+		rec, err := rr.next()
+		if err == io.EOF {
+			break
+		}
+		if pe, ok := err.(*parseError); ok {
+			mu.Lock()
+			stats.Read++
+			recordError(pe.line, pe.message)
+			mu.Unlock()
+			continue
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+
+		mu.Lock()
+		stats.Read++
+		if err := build(rec, pending); err != nil {
+			recordError(rec.line, err.Error())
+			mu.Unlock()
+			continue
+		}
+		mu.Unlock()
+		pending.lines = append(pending.lines, rec.line)
+
+		if pending.len() >= batchSize && !flush() {
+			break
+		}
+	}
+	if readErr == nil {
+		flush()
+	}
+	close(batches)
+	wg.Wait()
+
+	stats.Duration = time.Since(start)
+	switch {
+	case fatalErr != nil:
+		return stats, fatalErr
+	case readErr != nil:
+		return stats, errors.NewValidationError("Failed to read input", map[string]interface{}{"error": readErr.Error()})
+	case ctx.Err() != nil:
+		return stats, ctx.Err()
+	}
+	return stats, nil
+}
+
+// resolveEndpoint maps an edge endpoint value to a NenDB node ID
+func (im *Importer) resolveEndpoint(v interface{}, numeric bool) (int, error) {
+	key := stringValue(v)
+	if key == "" {
+		return 0, fmt.Errorf("missing value")
+	}
+	if id, ok := im.Resolve(key); ok {
+		return id, nil
+	}
+	if numeric {
+		if id, err := strconv.Atoi(key); err == nil && id >= 0 {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown node key %q", key)
+}
+
+// splitLabels turns a label column value into a list of labels
+func (im *Importer) splitLabels(v interface{}) []string {
+	var labels []string
+	switch val := v.(type) {
+	case []interface{}:
+		for _, l := range val {
+			if s := stringValue(l); s != "" {
+				labels = append(labels, s)
+			}
+		}
+	default:
+		for _, l := range strings.Split(stringValue(val), im.opts.LabelSeparator) {
+			if l = strings.TrimSpace(l); l != "" {
+				labels = append(labels, l)
+			}
+		}
+	}
+	return labels
+}
+
+// selectProperties picks the property columns of a record
+func selectProperties(fields map[string]interface{}, columns []string, exclude map[string]bool) map[string]interface{} {
+	props := make(map[string]interface{})
+	if len(columns) > 0 {
+		for _, c := range columns {
+			if v, ok := fields[c]; ok {
+				props[c] = v
+			}
+		}
+		return props
+	}
+	for k, v := range fields {
+		if k != "" && !exclude[k] {
+			props[k] = v
+		}
+	}
+	return props
+}
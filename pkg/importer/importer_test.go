@@ -0,0 +1,266 @@
+package importer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func newTestImporter(t *testing.T, opts Options) (*Importer, *nendbtest.Server) {
+	t.Helper()
+	srv := nendbtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	config := client.DefaultConfig()
+	config.BaseURL = srv.URL
+	config.MaxRetries = 0
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return New(c, opts), srv
+}
+
+func TestImportCSV(t *testing.T) {
+	var progress []Progress
+	im, srv := newTestImporter(t, Options{
+		BatchSize:   2,
+		Concurrency: 3,
+		InferTypes:  true,
+		Progress:    func(p Progress) { progress = append(progress, p) },
+	})
+	ctx := context.Background()
+
+	nodes := "id,name,age,kind\n" +
+		"alice,Alice,30,Admin;Employee\n" +
+		"bob,Bob,25,Employee\n" +
+		"carol,Carol,41,\n" +
+		",Nobody,1,\n" +
+		"bob,Bob Again,26,\n"
+
+	stats, err := im.ImportNodes(ctx, strings.NewReader(nodes), FormatCSV, NodeMapping{
+		KeyColumn:   "id",
+		Labels:      []string{"Person"},
+		LabelColumn: "kind",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error importing nodes, got %v", err)
+	}
+	if stats.Read != 5 || stats.Created != 3 || stats.Failed != 2 {
+		t.Errorf("Expected read=5 created=3 failed=2, got %+v", stats)
+	}
+	if len(stats.Errors) != 2 || stats.Errors[0].Line != 5 || stats.Errors[1].Line != 6 {
+		t.Errorf("Expected errors on lines 5 and 6, got %v", stats.Errors)
+	}
+	if len(progress) == 0 {
+		t.Error("Expected progress callbacks, got none")
+	}
+
+	aliceID, ok := im.Resolve("alice")
+	if !ok {
+		t.Fatal("Expected key 'alice' to be resolved")
+	}
+	alice := srv.Node(aliceID)
+	if len(alice.Labels) != 3 {
+		t.Errorf("Expected labels [Person Admin Employee], got %v", alice.Labels)
+	}
+	if alice.Properties["age"] == nil || alice.Properties["name"] != "Alice" {
+		t.Errorf("Expected typed properties, got %v", alice.Properties)
+	}
+	if _, ok := alice.Properties["kind"]; ok {
+		t.Error("Expected label column to be excluded from properties")
+	}
+
+	edges := "from,to,since\n" +
+		"alice,bob,2020\n" +
+		"bob,carol,2021\n" +
+		"alice,dave,2022\n"
+
+	stats, err = im.ImportEdges(ctx, strings.NewReader(edges), FormatCSV, EdgeMapping{
+		SourceColumn: "from",
+		TargetColumn: "to",
+		Type:         "KNOWS",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error importing edges, got %v", err)
+	}
+	if stats.Created != 2 || stats.Failed != 1 {
+		t.Errorf("Expected created=2 failed=1, got %+v", stats)
+	}
+
+	bobID, _ := im.Resolve("bob")
+	found := false
+	for _, e := range srv.Edges() {
+		if e.Source == aliceID && e.Target == bobID && e.Type == "KNOWS" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected alice-KNOWS->bob edge to be created")
+	}
+}
+
+func TestImportJSONL(t *testing.T) {
+	im, srv := newTestImporter(t, Options{})
+	ctx := context.Background()
+
+	nodes := `{"key": 1, "labels": ["City"], "name": "Oslo", "population": 709037}
+{"key": 2, "labels": ["City", "Capital"], "name": "Bergen"}
+
+{"key": 3, "labels": "City", "name": "Tromsø"}
+`
+	stats, err := im.ImportNodes(ctx, strings.NewReader(nodes), FormatJSONL, NodeMapping{
+		KeyColumn:   "key",
+		LabelColumn: "labels",
+		Properties:  []string{"name", "population"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error importing nodes, got %v", err)
+	}
+	if stats.Created != 3 {
+		t.Errorf("Expected 3 nodes created, got %+v", stats)
+	}
+
+	id, _ := im.Resolve("2")
+	if node := srv.Node(id); len(node.Labels) != 2 || len(node.Properties) != 1 {
+		t.Errorf("Expected 2 labels and 1 property, got %+v", node)
+	}
+
+	edges := `{"a": 1, "b": 2, "rel": "ROAD", "km": 463}
+{"a": 2, "b": 3, "km": 1000}
+`
+	stats, err = im.ImportEdges(ctx, strings.NewReader(edges), FormatJSONL, EdgeMapping{
+		SourceColumn: "a",
+		TargetColumn: "b",
+		Type:         "CONNECTED",
+		TypeColumn:   "rel",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error importing edges, got %v", err)
+	}
+	if stats.Created != 2 {
+		t.Errorf("Expected 2 edges created, got %+v", stats)
+	}
+	edgeTypes := map[string]bool{}
+	for _, e := range srv.Edges() {
+		edgeTypes[e.Type] = true
+	}
+	if !edgeTypes["ROAD"] || !edgeTypes["CONNECTED"] {
+		t.Errorf("Expected ROAD and CONNECTED edges, got %v", edgeTypes)
+	}
+}
+
+func TestImportResolveNumericIDs(t *testing.T) {
+	im, srv := newTestImporter(t, Options{})
+	a := srv.AddNode(nil, nil)
+	b := srv.AddNode(nil, nil)
+
+	edges := "source,target\n" + strconv.Itoa(a.ID) + "," + strconv.Itoa(b.ID) + "\n"
+	stats, err := im.ImportEdges(context.Background(), strings.NewReader(edges), FormatCSV, EdgeMapping{
+		SourceColumn:      "source",
+		TargetColumn:      "target",
+		Type:              "LINKS",
+		ResolveNumericIDs: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Created != 1 {
+		t.Errorf("Expected 1 edge created, got %+v", stats)
+	}
+}
+
+func TestImportMalformedRows(t *testing.T) {
+	im, _ := newTestImporter(t, Options{BatchSize: 2, Concurrency: 1})
+	ctx := context.Background()
+
+	csvInput := "id,name\n" +
+		"a,A\n" +
+		"b,B,extra\n" +
+		"c\n" +
+		"d,\"D\"x\n" +
+		"e,E\n"
+	stats, err := im.ImportNodes(ctx, strings.NewReader(csvInput), FormatCSV, NodeMapping{KeyColumn: "id"})
+	if err != nil {
+		t.Fatalf("Expected malformed rows not to abort the import, got %v", err)
+	}
+	if stats.Read != 5 || stats.Created != 2 || stats.Failed != 3 {
+		t.Errorf("Expected read=5 created=2 failed=3, got %+v", stats)
+	}
+	if len(stats.Errors) != 3 || stats.Errors[0].Line != 3 || stats.Errors[1].Line != 4 || stats.Errors[2].Line != 5 {
+		t.Errorf("Expected errors on lines 3, 4 and 5, got %v", stats.Errors)
+	}
+	if _, ok := im.Resolve("e"); !ok {
+		t.Error("Expected rows after malformed ones to be imported")
+	}
+
+	jsonInput := `{"id": "f"}` + "\n" +
+		`{"id": ` + "\n" +
+		`["g"]` + "\n" +
+		`{"id": "h"}` + "\n" +
+		`{"id": "i", "$bad": 1}` + "\n"
+	stats, err = im.ImportNodes(ctx, strings.NewReader(jsonInput), FormatJSONL, NodeMapping{KeyColumn: "id"})
+	if err != nil {
+		t.Fatalf("Expected invalid lines not to abort the import, got %v", err)
+	}
+	if stats.Read != 5 || stats.Created != 2 || len(stats.Errors) != 3 || stats.Errors[0].Line != 2 || stats.Errors[1].Line != 3 || stats.Errors[2].Line != 5 {
+		t.Errorf("Expected lines 2, 3 and 5 to fail alone, got %+v", stats)
+	}
+	if len(stats.Errors) == 3 && !strings.Contains(stats.Errors[2].Message, "$bad") {
+		t.Errorf("Expected the invalid property to be reported, got %q", stats.Errors[2].Message)
+	}
+}
+
+func TestImportUsesClientBatchSize(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	config := client.DefaultConfig()
+	config.BaseURL = srv.URL
+	config.BatchSize = 2
+	config.SkipValidation = true
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	im := New(c, Options{Concurrency: 1})
+
+	stats, err := im.ImportNodes(context.Background(), strings.NewReader("id\na\nb\nc\nd\ne\n"), FormatCSV, NodeMapping{KeyColumn: "id"})
+	if err != nil || stats.Created != 5 {
+		t.Fatalf("Expected 5 nodes, got %+v, %v", stats, err)
+	}
+	if srv.Requests() != 3 {
+		t.Errorf("Expected batches of the client's size 2 in 3 requests, got %d", srv.Requests())
+	}
+}
+
+func TestImportFatalError(t *testing.T) {
+	im, srv := newTestImporter(t, Options{})
+	srv.Close()
+
+	_, err := im.ImportNodes(context.Background(), strings.NewReader("id\n1\n"), FormatCSV, NodeMapping{KeyColumn: "id"})
+	if err == nil {
+		t.Error("Expected error when server is unreachable, got nil")
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"people.csv":   FormatCSV,
+		"PEOPLE.CSV":   FormatCSV,
+		"edges.jsonl":  FormatJSONL,
+		"edges.ndjson": FormatJSONL,
+	}
+	for path, want := range tests {
+		got, err := FormatFromPath(path)
+		if err != nil || got != want {
+			t.Errorf("Expected %s for %s, got %s (%v)", want, path, got, err)
+		}
+	}
+	if _, err := FormatFromPath("data.xml"); err == nil {
+		t.Error("Expected error for unknown extension, got nil")
+	}
+}
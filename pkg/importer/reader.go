@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format identifies the encoding of an input file
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// FormatFromPath infers the input format from a file extension
+func FormatFromPath(path string) (Format, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return FormatCSV, nil
+	case strings.HasSuffix(lower, ".jsonl"), strings.HasSuffix(lower, ".ndjson"):
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("cannot infer format of %s (expected .csv, .jsonl or .ndjson)", path)
+	}
+}
+
+// record is a single input row keyed by column name
+type record struct {
+	line   int
+	fields map[string]interface{}
+}
+
+// recordReader streams records from an input file
+type recordReader interface {
+	// next returns the next record, or io.EOF when the input is exhausted.
+	// A *parseError reports a malformed row; reading may continue after it.
+	next() (*record, error)
+}
+
+// parseError is a row that could not be parsed
+type parseError struct {
+	line    int
+	message string
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.message)
+}
+
+func newRecordReader(r io.Reader, format Format, inferTypes bool) (recordReader, error) {
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.ReuseRecord = true
+		// Rows of the wrong width are reported by csvReader.next, so that
+		// they fail alone rather than ending the import
+		cr.FieldsPerRecord = -1
+		header, err := cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("csv input has no header row")
+			}
+			return nil, fmt.Errorf("failed to read csv header: %v", err)
+		}
+		return &csvReader{r: cr, header: append([]string{}, header...), inferTypes: inferTypes}, nil
+	case FormatJSONL:
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		return &jsonlReader{sc: sc}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %q", format)
+	}
+}
+
+type csvReader struct {
+	r          *csv.Reader
+	header     []string
+	inferTypes bool
+}
+
+func (c *csvReader) next() (*record, error) {
+	row, err := c.r.Read()
+	if pe, ok := err.(*csv.ParseError); ok {
+		return nil, &parseError{line: pe.StartLine, message: pe.Err.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+	line, _ := c.r.FieldPos(0)
+	if len(row) != len(c.header) {
+		return nil, &parseError{line: line, message: fmt.Sprintf("expected %d fields, got %d", len(c.header), len(row))}
+	}
+
+	fields := make(map[string]interface{}, len(row))
+	for i, value := range row {
+		if value == "" {
+			continue
+		}
+		if c.inferTypes {
+			fields[c.header[i]] = inferValue(value)
+		} else {
+			fields[c.header[i]] = value
+		}
+	}
+	return &record{line: line, fields: fields}, nil
+}
+
+type jsonlReader struct {
+	sc   *bufio.Scanner
+	line int
+}
+
+func (j *jsonlReader) next() (*record, error) {
+	for j.sc.Scan() {
+		j.line++
+		text := bytes.TrimSpace(j.sc.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.UseNumber()
+		var fields map[string]interface{}
+		if err := dec.Decode(&fields); err != nil {
+			return nil, &parseError{line: j.line, message: fmt.Sprintf("invalid JSON object: %v", err)}
+		}
+		return &record{line: j.line, fields: fields}, nil
+	}
+	if err := j.sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// inferValue converts a CSV cell to a bool, integer or float when it parses as one
+func inferValue(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// stringValue renders a field used as a key or label
+func stringValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
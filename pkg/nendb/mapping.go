@@ -0,0 +1,585 @@
+package nendb
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// TagName is the struct tag read by the mapper
+const TagName = "nendb"
+
+// structInfo describes how a struct type maps to a node
+type structInfo struct {
+	labels []string
+	// id is the position in fields of the node ID, or -1 if there is none.
+	// Nested structs store it as an ordinary property.
+	id         int
+	idExplicit bool
+	fields     []fieldInfo
+}
+
+// fieldInfo describes a single mapped field
+type fieldInfo struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var structInfoCache sync.Map // reflect.Type -> *structInfo
+
+// structInfoFor returns the mapping for struct type t
+func structInfoFor(t reflect.Type) (*structInfo, error) {
+	if cached, ok := structInfoCache.Load(t); ok {
+		return cached.(*structInfo), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, errors.NewValidationError(fmt.Sprintf("Cannot map %s to a node; a struct is required", t), map[string]interface{}{"type": t.String()})
+	}
+
+	info := &structInfo{id: -1}
+	if err := info.addFields(t, nil); err != nil {
+		return nil, errors.NewValidationError(fmt.Sprintf("Invalid nendb tags on %s", t), map[string]interface{}{"type": t.String(), "error": err.Error()})
+	}
+	if len(info.labels) == 0 && t.Name() != "" {
+		info.labels = []string{t.Name()}
+	}
+
+	cached, _ := structInfoCache.LoadOrStore(t, info)
+	return cached.(*structInfo), nil
+}
+
+// addFields collects the mapped fields of t, flattening untagged embedded
+// structs into their parent
+func (info *structInfo) addFields(t reflect.Type, index []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, err := parseTag(f)
+		if err != nil {
+			return err
+		}
+		info.labels = append(info.labels, opts.labels...)
+
+		fieldIndex := append(append([]int{}, index...), i)
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			if err := info.addFields(f.Type, fieldIndex); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		field := fieldInfo{name: name, index: fieldIndex, omitEmpty: opts.omitEmpty}
+		for _, existing := range info.fields {
+			if existing.name == name {
+				return fmt.Errorf("duplicate property name %q", name)
+			}
+		}
+		info.fields = append(info.fields, field)
+
+		switch {
+		case opts.id:
+			if !isIDType(f.Type) {
+				return fmt.Errorf("ID field %s must be an integer or a pointer to one", f.Name)
+			}
+			if info.idExplicit {
+				return fmt.Errorf("more than one ID field")
+			}
+			info.id, info.idExplicit = len(info.fields)-1, true
+		case f.Name == "ID" && name == "ID" && info.id < 0 && isIDType(f.Type):
+			info.id = len(info.fields) - 1
+		}
+	}
+	return nil
+}
+
+// tagOptions are the options following the property name in a nendb tag
+type tagOptions struct {
+	omitEmpty bool
+	id        bool
+	labels    []string
+}
+
+// parseTag reads the nendb tag of f
+func parseTag(f reflect.StructField) (string, tagOptions, error) {
+	var opts tagOptions
+	tag, ok := f.Tag.Lookup(TagName)
+	if !ok {
+		return "", opts, nil
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "omitempty":
+			opts.omitEmpty = true
+		case opt == "id":
+			opts.id = true
+		case strings.HasPrefix(opt, "label="):
+			label := strings.TrimPrefix(opt, "label=")
+			if label == "" {
+				return "", opts, fmt.Errorf("field %s: empty label", f.Name)
+			}
+			opts.labels = append(opts.labels, label)
+		default:
+			return "", opts, fmt.Errorf("field %s: unknown tag option %q", f.Name, opt)
+		}
+	}
+	return parts[0], opts, nil
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isIDType reports whether t can hold a node ID: an integer, or a pointer to
+// one that is nil until the node is saved
+func isIDType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return isIntKind(t.Kind())
+}
+
+// getID returns the node ID stored in v and whether v has been saved. An
+// integer ID of 0 or a nil pointer ID means unsaved, as does having no ID
+// field.
+func (info *structInfo) getID(v reflect.Value) (int, bool) {
+	if info.id < 0 {
+		return 0, false
+	}
+	field := v.FieldByIndex(info.fields[info.id].index)
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return 0, false
+		}
+		return int(field.Elem().Int()), true
+	}
+	id := int(field.Int())
+	return id, id != 0
+}
+
+// setID stores a node ID in v, if the type has an ID field
+func (info *structInfo) setID(v reflect.Value, id int) {
+	if info.id < 0 {
+		return
+	}
+	field := v.FieldByIndex(info.fields[info.id].index)
+	if field.Kind() == reflect.Pointer {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+	field.SetInt(int64(id))
+}
+
+// pathError locates an encoding or decoding failure within the properties
+type pathError struct {
+	path string
+	err  error
+}
+
+func (e *pathError) Error() string {
+	return e.path + ": " + e.err.Error()
+}
+
+// atPath prefixes err's location with name
+func atPath(name string, err error) error {
+	var pe *pathError
+	if stderrors.As(err, &pe) {
+		sep := "."
+		if strings.HasPrefix(pe.path, "[") {
+			sep = ""
+		}
+		return &pathError{path: name + sep + pe.path, err: pe.err}
+	}
+	return &pathError{path: name, err: err}
+}
+
+// mappingError converts an encoding or decoding failure into a validation
+// error
+func mappingError(message string, err error) error {
+	details := map[string]interface{}{"error": err.Error()}
+	var pe *pathError
+	if stderrors.As(err, &pe) {
+		details["property"] = pe.path
+		details["error"] = pe.err.Error()
+	}
+	return errors.NewValidationError(message, details)
+}
+
+// encodeStruct converts the node fields of v into properties
+func encodeStruct(v reflect.Value, info *structInfo) (map[string]interface{}, error) {
+	properties, err := encodeFields(v, info, false)
+	if err != nil {
+		return nil, mappingError("Failed to encode node properties", err)
+	}
+	return properties, nil
+}
+
+// encodeFields converts the fields of v into a property map. The ID field is
+// only included for nested structs.
+func encodeFields(v reflect.Value, info *structInfo, nested bool) (map[string]interface{}, error) {
+	properties := make(map[string]interface{}, len(info.fields))
+	for i := range info.fields {
+		f := info.fields[i]
+		if i == info.id && !nested {
+			continue
+		}
+		fv := v.FieldByIndex(f.index)
+		if f.omitEmpty && isEmpty(fv) {
+			continue
+		}
+		value, err := encodeValue(fv)
+		if err != nil {
+			return nil, atPath(f.name, err)
+		}
+		properties[f.name] = value
+	}
+	return properties, nil
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+var (
+	propertyMarshalerType   = reflect.TypeFor[PropertyMarshaler]()
+	propertyUnmarshalerType = reflect.TypeFor[PropertyUnmarshaler]()
+	textMarshalerType       = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType     = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+	// typedValueTypes are stored as typed property values rather than being
+	// taken apart or marshaled as text
+	typedValueTypes = map[reflect.Type]bool{
+		reflect.TypeFor[time.Time]():      true,
+		reflect.TypeFor[time.Duration]():  true,
+		reflect.TypeFor[types.GeoPoint](): true,
+		reflect.TypeFor[[]byte]():         true,
+	}
+)
+
+// encodeValue converts v into a value the server can store
+func encodeValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if v.Kind() != reflect.Pointer && v.CanAddr() && v.Addr().Type().Implements(propertyMarshalerType) {
+		v = v.Addr()
+	}
+	if v.Type().Implements(propertyMarshalerType) {
+		value, err := v.Interface().(PropertyMarshaler).MarshalProperty()
+		if err != nil {
+			return nil, err
+		}
+		return encodeValue(reflect.ValueOf(value))
+	}
+	if typedValueTypes[v.Type()] {
+		return v.Interface(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return encodeValue(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return bytesOf(v), nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			value, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, atPath(fmt.Sprintf("[%d]", i), err)
+			}
+			list[i] = value
+		}
+		return list, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			value, err := encodeValue(iter.Value())
+			if err != nil {
+				return nil, atPath(key, err)
+			}
+			m[key] = value
+		}
+		return m, nil
+	case reflect.Struct:
+		info, err := structInfoFor(v.Type())
+		if err != nil {
+			return nil, err
+		}
+		return encodeFields(v, info, true)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// bytesOf returns the contents of a byte slice or array
+func bytesOf(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+	return b
+}
+
+// decodeStruct fills the node fields of v from properties
+func decodeStruct(v reflect.Value, info *structInfo, properties map[string]interface{}) error {
+	if err := decodeFields(v, info, properties, false); err != nil {
+		return mappingError("Failed to decode node properties", err)
+	}
+	return nil
+}
+
+// decodeFields fills the fields of v from a property map. The ID field is
+// only read from the map for nested structs.
+func decodeFields(v reflect.Value, info *structInfo, properties map[string]interface{}, nested bool) error {
+	for i := range info.fields {
+		f := info.fields[i]
+		if i == info.id && !nested {
+			continue
+		}
+		value, ok := properties[f.name]
+		if !ok {
+			continue
+		}
+		if err := decodeValue(fieldByIndexAlloc(v, f.index), value); err != nil {
+			return atPath(f.name, err)
+		}
+	}
+	return nil
+}
+
+// fieldByIndexAlloc is reflect.Value.FieldByIndex for settable values
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// decodeValue stores src, a property value as decoded from JSON, in dst
+func decodeValue(dst reflect.Value, src interface{}) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeValue(dst.Elem(), src)
+	}
+
+	ptr := dst.Addr()
+	if ptr.Type().Implements(propertyUnmarshalerType) {
+		return ptr.Interface().(PropertyUnmarshaler).UnmarshalProperty(src)
+	}
+	if sv := reflect.ValueOf(src); dst.Kind() != reflect.Interface && sv.Type().AssignableTo(dst.Type()) {
+		dst.Set(sv)
+		return nil
+	}
+	if s, ok := src.(string); ok && ptr.Type().Implements(textUnmarshalerType) {
+		return ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return fmt.Errorf("cannot decode into non-empty interface %s", dst.Type())
+		}
+		dst.Set(reflect.ValueOf(src))
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return mismatch(src, dst)
+		}
+		dst.SetBool(b)
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return mismatch(src, dst)
+		}
+		dst.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(numberText(src), 10, 64)
+		if err != nil || dst.OverflowInt(n) {
+			return mismatch(src, dst)
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(numberText(src), 10, 64)
+		if err != nil || dst.OverflowUint(n) {
+			return mismatch(src, dst)
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(numberText(src), 64)
+		if err != nil || dst.OverflowFloat(f) {
+			return mismatch(src, dst)
+		}
+		dst.SetFloat(f)
+	case reflect.Slice, reflect.Array:
+		return decodeList(dst, src)
+	case reflect.Map:
+		m, ok := src.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return mismatch(src, dst)
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), len(m))
+		for key, value := range m {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeValue(elem, value); err != nil {
+				return atPath(key, err)
+			}
+			out.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(out)
+	case reflect.Struct:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			return mismatch(src, dst)
+		}
+		info, err := structInfoFor(dst.Type())
+		if err != nil {
+			return err
+		}
+		return decodeFields(dst, info, m, true)
+	default:
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+	return nil
+}
+
+// decodeList stores a list property in dst. Byte slices and arrays also
+// accept bytes values and, as written by earlier versions, base64 text.
+func decodeList(dst reflect.Value, src interface{}) error {
+	if dst.Type().Elem().Kind() == reflect.Uint8 {
+		switch b := src.(type) {
+		case []byte:
+			return setBytes(dst, b)
+		case string:
+			decoded, err := base64.StdEncoding.DecodeString(b)
+			if err != nil {
+				return err
+			}
+			return setBytes(dst, decoded)
+		}
+	}
+
+	list, ok := src.([]interface{})
+	if !ok {
+		return mismatch(src, dst)
+	}
+	if dst.Kind() == reflect.Array {
+		if len(list) > dst.Len() {
+			return fmt.Errorf("list of %d values does not fit in %s", len(list), dst.Type())
+		}
+		dst.SetZero()
+	} else {
+		dst.Set(reflect.MakeSlice(dst.Type(), len(list), len(list)))
+	}
+	for i, value := range list {
+		if err := decodeValue(dst.Index(i), value); err != nil {
+			return atPath(fmt.Sprintf("[%d]", i), err)
+		}
+	}
+	return nil
+}
+
+// setBytes copies b into a byte slice or array
+func setBytes(dst reflect.Value, b []byte) error {
+	if dst.Kind() == reflect.Array {
+		if len(b) > dst.Len() {
+			return fmt.Errorf("%d bytes do not fit in %s", len(b), dst.Type())
+		}
+		dst.SetZero()
+	} else {
+		dst.Set(reflect.MakeSlice(dst.Type(), len(b), len(b)))
+	}
+	reflect.Copy(dst, reflect.ValueOf(b))
+	return nil
+}
+
+// numberText returns the text of a numeric property value, or an empty
+// string if src is not a number
+func numberText(src interface{}) string {
+	switch n := src.(type) {
+	case json.Number:
+		return n.String()
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(n), 'f', -1, 32)
+	case int:
+		return strconv.Itoa(n)
+	case int64:
+		return strconv.FormatInt(n, 10)
+	case uint64:
+		return strconv.FormatUint(n, 10)
+	}
+	return ""
+}
+
+func mismatch(src interface{}, dst reflect.Value) error {
+	return fmt.Errorf("cannot decode %T %v into %s", src, src, dst.Type())
+}
@@ -0,0 +1,176 @@
+// Package nendb maps Go structs to NenDB nodes.
+//
+// Struct fields become node properties, named by the nendb struct tag or, when
+// untagged, by the field name. A field named ID, or tagged with the id option,
+// holds the node ID. The label option, usually on a blank field, sets the
+// node's labels; without it the struct's type name is used:
+//
+//	type Person struct {
+//		_       struct{}  `nendb:",label=Person"`
+//		ID      int       `nendb:",id"`
+//		Name    string    `nendb:"name"`
+//		Born    time.Time `nendb:"born,omitempty"`
+//		Address Address   `nendb:"address"`
+//		Tags    []string  `nendb:"tags"`
+//		Secret  string    `nendb:"-"`
+//	}
+//
+//	p := Person{Name: "Alice"}
+//	err := nendb.Save(ctx, c, &p) // p.ID now holds the node ID
+//	alice, err := nendb.Get[Person](ctx, c, p.ID)
+//
+// Nested structs and maps are stored as map properties and slices as lists.
+// time.Time, time.Duration, []byte and types.GeoPoint are stored as typed
+// property values, and other encoding.TextMarshaler implementations as text.
+// Types can control their own encoding by implementing PropertyMarshaler and
+// PropertyUnmarshaler.
+package nendb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// PropertyMarshaler is implemented by types that encode themselves as a
+// property value. The returned value must itself be encodable.
+type PropertyMarshaler interface {
+	MarshalProperty() (interface{}, error)
+}
+
+// PropertyUnmarshaler is implemented by types that decode themselves from a
+// property value as returned by the server
+type PropertyUnmarshaler interface {
+	UnmarshalProperty(value interface{}) error
+}
+
+// Save stores v as a node. An unsaved v is created and the assigned ID is
+// written back to it; otherwise the node with v's ID is updated, replacing its
+// labels and properties.
+//
+// An integer ID of 0 means unsaved, so a node with ID 0 can only be updated
+// through a pointer ID field, which is nil until the node is saved:
+//
+//	type Person struct {
+//		ID   *int   `nendb:",id"`
+//		Name string `nendb:"name"`
+//	}
+func Save[T any](ctx context.Context, c *client.NenDBClient, v *T) error {
+	node, err := MarshalNode(v)
+	if err != nil {
+		return err
+	}
+
+	info, _ := structInfoFor(reflect.TypeOf(v).Elem())
+	var saved *types.GraphNode
+	if _, ok := info.getID(reflect.ValueOf(v).Elem()); !ok {
+		saved, err = c.CreateNode(ctx, node.Labels, node.Properties)
+	} else {
+		saved, err = c.UpdateNode(ctx, node.ID, node.Labels, node.Properties)
+	}
+	if err != nil {
+		return err
+	}
+
+	info.setID(reflect.ValueOf(v).Elem(), saved.ID)
+	return nil
+}
+
+// Get fetches the node with the given ID and decodes it into a new T. The
+// node must carry all of T's labels.
+func Get[T any](ctx context.Context, c *client.NenDBClient, id int) (*T, error) {
+	v := new(T)
+	if _, err := structInfoFor(reflect.TypeOf(v).Elem()); err != nil {
+		return nil, err
+	}
+
+	node, err := c.GetNode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := UnmarshalNode(node, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Delete deletes the node with v's ID. Deleting an unsaved v is an error.
+func Delete[T any](ctx context.Context, c *client.NenDBClient, v *T) error {
+	info, err := structInfoFor(reflect.TypeOf(v).Elem())
+	if err != nil {
+		return err
+	}
+	id, ok := info.getID(reflect.ValueOf(v).Elem())
+	if !ok {
+		return errors.NewValidationError("Cannot delete a node that has not been saved", map[string]interface{}{"type": reflect.TypeOf(v).Elem().String()})
+	}
+	return c.DeleteNode(ctx, id)
+}
+
+// MarshalNode converts a struct, or a pointer to one, into a node
+func MarshalNode(v interface{}) (*types.GraphNode, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errors.NewValidationError("Cannot marshal a nil pointer", nil)
+		}
+		rv = rv.Elem()
+	}
+	info, err := structInfoFor(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := encodeStruct(rv, info)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := info.getID(rv)
+	return &types.GraphNode{
+		ID:         id,
+		Labels:     append([]string{}, info.labels...),
+		Properties: properties,
+	}, nil
+}
+
+// UnmarshalNode decodes node into the struct pointed to by v. Properties
+// without a matching field are ignored.
+func UnmarshalNode(node *types.GraphNode, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.NewValidationError("UnmarshalNode requires a non-nil pointer", map[string]interface{}{"type": fmt.Sprintf("%T", v)})
+	}
+	rv = rv.Elem()
+	info, err := structInfoFor(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, label := range info.labels {
+		if !hasLabel(node.Labels, label) {
+			return errors.NewValidationError(
+				fmt.Sprintf("Node %d does not have label %q", node.ID, label),
+				map[string]interface{}{"node_id": node.ID, "labels": node.Labels, "type": rv.Type().String()},
+			)
+		}
+	}
+
+	if err := decodeStruct(rv, info, node.Properties); err != nil {
+		return err
+	}
+	info.setID(rv, node.ID)
+	return nil
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+package nendb
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/errors"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+type Address struct {
+	City    string `nendb:"city"`
+	Country string `nendb:"country,omitempty"`
+}
+
+// Celsius is stored as text with a unit suffix
+type Celsius float64
+
+func (c Celsius) MarshalProperty() (interface{}, error) {
+	return fmt.Sprintf("%gC", float64(c)), nil
+}
+
+func (c *Celsius) UnmarshalProperty(value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected string, got %T", value)
+	}
+	_, err := fmt.Sscanf(strings.TrimSuffix(s, "C"), "%g", (*float64)(c))
+	return err
+}
+
+type Audit struct {
+	UpdatedBy string `nendb:"updated_by"`
+}
+
+type Person struct {
+	_        struct{}          `nendb:",label=Person,label=Employee"`
+	Key      int64             `nendb:",id"`
+	Name     string            `nendb:"name"`
+	Age      int               `nendb:"age,omitempty"`
+	Born     time.Time         `nendb:"born"`
+	Address  Address           `nendb:"address"`
+	Previous []Address         `nendb:"previous"`
+	Tags     []string          `nendb:"tags"`
+	Scores   map[string]uint16 `nendb:"scores"`
+	Avatar   []byte            `nendb:"avatar"`
+	Temp     Celsius           `nendb:"temp"`
+	Home     types.GeoPoint    `nendb:"home"`
+	Shift    time.Duration     `nendb:"shift"`
+	Manager  *string           `nendb:"manager"`
+	Password string            `nendb:"-"`
+	Note     string
+	Audit
+}
+
+func newClient(t *testing.T, srv *nendbtest.Server) *client.NenDBClient {
+	t.Helper()
+	config := client.DefaultConfig()
+	config.BaseURL = srv.URL
+	config.MaxRetries = 0
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return c
+}
+
+func TestSaveAndGetRoundTrip(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	manager := "Carol"
+	p := Person{
+		Name:     "Alice",
+		Born:     time.Date(1990, 4, 1, 12, 30, 0, 0, time.UTC),
+		Address:  Address{City: "Lisbon", Country: "PT"},
+		Previous: []Address{{City: "Porto"}},
+		Tags:     []string{"admin", "ops"},
+		Scores:   map[string]uint16{"go": 9},
+		Avatar:   []byte{0, 1, 2, 255},
+		Temp:     36.6,
+		Home:     types.GeoPoint{Lat: 38.7, Lon: -9.1},
+		Shift:    8 * time.Hour,
+		Manager:  &manager,
+		Password: "secret",
+		Note:     "untagged",
+		Audit:    Audit{UpdatedBy: "bob"},
+	}
+	if err := Save(ctx, c, &p); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if p.Key == 0 {
+		t.Fatal("Expected Save to assign the node ID")
+	}
+
+	stored := srv.Node(int(p.Key))
+	if !reflect.DeepEqual(stored.Labels, []string{"Person", "Employee"}) {
+		t.Errorf("Expected labels from tag, got %v", stored.Labels)
+	}
+	for _, key := range []string{"age", "Password", "Key"} {
+		if _, ok := stored.Properties[key]; ok {
+			t.Errorf("Expected property %q not to be stored", key)
+		}
+	}
+	if born, ok := stored.Properties["born"].(map[string]interface{}); !ok || born["$time"] != "1990-04-01T12:30:00Z" {
+		t.Errorf("Expected born to be sent as a time value, got %v", stored.Properties["born"])
+	}
+	if stored.Properties["temp"] != "36.6C" {
+		t.Errorf("Expected custom encoding for temp, got %v", stored.Properties["temp"])
+	}
+	if stored.Properties["Note"] != "untagged" || stored.Properties["updated_by"] != "bob" {
+		t.Errorf("Expected untagged and embedded fields to be stored, got %v", stored.Properties)
+	}
+
+	got, err := Get[Person](ctx, c, int(p.Key))
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	p.Password = ""
+	if !reflect.DeepEqual(*got, p) {
+		t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", *got, p)
+	}
+
+	p.Name = "Alicia"
+	if err := Save(ctx, c, &p); err != nil {
+		t.Fatalf("Save (update) failed: %v", err)
+	}
+	if len(srv.Nodes()) != 1 || srv.Node(int(p.Key)).Properties["name"] != "Alicia" {
+		t.Errorf("Expected Save to update the existing node")
+	}
+
+	if err := Delete(ctx, c, &p); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if len(srv.Nodes()) != 0 {
+		t.Error("Expected node to be deleted")
+	}
+}
+
+type City struct {
+	ID         int
+	Name       string `nendb:"name"`
+	Population int64  `nendb:"population"`
+}
+
+func TestDefaultLabelAndImplicitID(t *testing.T) {
+	node, err := MarshalNode(City{ID: 7, Name: "Lisbon", Population: 545000})
+	if err != nil {
+		t.Fatalf("MarshalNode failed: %v", err)
+	}
+	if node.ID != 7 || !reflect.DeepEqual(node.Labels, []string{"City"}) {
+		t.Errorf("Expected ID 7 and label City, got %d and %v", node.ID, node.Labels)
+	}
+	if _, ok := node.Properties["ID"]; ok {
+		t.Error("Expected ID not to be stored as a property")
+	}
+}
+
+func TestUnmarshalNodeErrors(t *testing.T) {
+	var city City
+	err := UnmarshalNode(&types.GraphNode{ID: 1, Labels: []string{"Person"}}, &city)
+	if err == nil || !strings.Contains(err.Error(), "label") {
+		t.Errorf("Expected label mismatch error, got %v", err)
+	}
+
+	err = UnmarshalNode(&types.GraphNode{ID: 1, Labels: []string{"City"}, Properties: map[string]interface{}{"population": 1.5}}, &city)
+	var valErr *errors.NenDBValidationError
+	if !stderrors.As(err, &valErr) || valErr.Details["property"] != "population" {
+		t.Errorf("Expected validation error for population, got %v", err)
+	}
+
+	var p Person
+	err = UnmarshalNode(&types.GraphNode{ID: 1, Labels: []string{"Person", "Employee"}, Properties: map[string]interface{}{
+		"previous": []interface{}{map[string]interface{}{"city": 3.0}},
+	}}, &p)
+	if !stderrors.As(err, &valErr) || valErr.Details["property"] != "previous[0].city" {
+		t.Errorf("Expected validation error for previous[0].city, got %v", err)
+	}
+}
+
+func TestSaveNodeZero(t *testing.T) {
+	// A server whose first node gets ID 0
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, `{"id": 0, "labels": ["Sensor"], "properties": {"name": "s0"}}`)
+	}))
+	defer srv.Close()
+	config := client.DefaultConfig()
+	config.BaseURL = srv.URL
+	config.SkipValidation = true
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	type Sensor struct {
+		ID   *int   `nendb:",id"`
+		Name string `nendb:"name"`
+	}
+	s := Sensor{Name: "s0"}
+	if err := Delete(ctx, c, &s); err == nil {
+		t.Error("Expected deleting an unsaved node to fail")
+	}
+	if err := Save(ctx, c, &s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if s.ID == nil || *s.ID != 0 {
+		t.Fatalf("Expected ID 0 to be set, got %v", s.ID)
+	}
+	if err := Save(ctx, c, &s); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if want := []string{"POST /nodes", "PUT /nodes/0"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("Expected node 0 to be created then updated, got %q", requests)
+	}
+
+	var loaded Sensor
+	if err := UnmarshalNode(&types.GraphNode{ID: 0, Labels: []string{"Sensor"}}, &loaded); err != nil || loaded.ID == nil || *loaded.ID != 0 {
+		t.Errorf("Expected decoding to set a pointer ID, got %v, %v", loaded.ID, err)
+	}
+}
+
+func TestInvalidTypes(t *testing.T) {
+	type badTag struct {
+		Name string `nendb:"name,unique"`
+	}
+	type badID struct {
+		Key string `nendb:",id"`
+	}
+	type badValue struct {
+		Ch chan int
+	}
+	for _, v := range []interface{}{42, badTag{}, badID{}, badValue{Ch: make(chan int)}} {
+		if _, err := MarshalNode(v); err == nil {
+			t.Errorf("Expected MarshalNode(%T) to fail", v)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package nendbtest
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// pageRankDamping is the damping factor used by the fake PageRank
+const pageRankDamping = 0.85
+
+func (s *Server) handleBFS(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StartNode  int `json:"start_node"`
+		TargetNode int `json:"target_node"`
+		MaxDepth   int `json:"max_depth"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.nodes[req.StartNode]; !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("start node %d not found", req.StartNode))
+		return
+	}
+
+	adjacency := s.adjacency()
+	parent := map[int]int{req.StartNode: req.StartNode}
+	visited := []int{req.StartNode}
+	frontier := []int{req.StartNode}
+	depth := 0
+	found := req.StartNode == req.TargetNode
+
+	for len(frontier) > 0 && !found && (req.MaxDepth <= 0 || depth < req.MaxDepth) {
+		var next []int
+		for _, id := range frontier {
+			for _, e := range adjacency[id] {
+				if _, seen := parent[e.Target]; seen {
+					continue
+				}
+				parent[e.Target] = id
+				visited = append(visited, e.Target)
+				next = append(next, e.Target)
+				if e.Target == req.TargetNode {
+					found = true
+				}
+			}
+		}
+		frontier = next
+		if len(next) > 0 {
+			depth++
+		}
+	}
+
+	path := []int{}
+	message := fmt.Sprintf("target node %d not reachable from %d", req.TargetNode, req.StartNode)
+	if found {
+		for id := req.TargetNode; ; id = parent[id] {
+			path = append([]int{id}, path...)
+			if id == req.StartNode {
+				break
+			}
+		}
+		message = fmt.Sprintf("found path of length %d", len(path)-1)
+	}
+
+	base, _ := types.NewAlgorithmResult("bfs", types.StatusCompleted, message, nil)
+	writeJSON(w, http.StatusOK, types.NewBFSResult(base, visited, path, depth))
+}
+
+func (s *Server) handleDijkstra(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StartNode  int `json:"start_node"`
+		TargetNode int `json:"target_node"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, id := range []int{req.StartNode, req.TargetNode} {
+		if _, ok := s.nodes[id]; !ok {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("node %d not found", id))
+			return
+		}
+	}
+
+	adjacency := s.adjacency()
+	dist := map[int]float64{req.StartNode: 0}
+	via := make(map[int]*types.GraphEdge)
+	done := make(map[int]bool)
+	pq := &distanceQueue{{node: req.StartNode}}
+
+	for pq.Len() > 0 {
+		cur := heap.Pop(pq).(distanceItem)
+		if done[cur.node] {
+			continue
+		}
+		done[cur.node] = true
+		if cur.node == req.TargetNode {
+			break
+		}
+		for _, e := range adjacency[cur.node] {
+			d := cur.dist + edgeWeight(e)
+			if old, ok := dist[e.Target]; !ok || d < old {
+				dist[e.Target] = d
+				via[e.Target] = e
+				heap.Push(pq, distanceItem{node: e.Target, dist: d})
+			}
+		}
+	}
+
+	base, _ := types.NewAlgorithmResult("dijkstra", types.StatusCompleted,
+		fmt.Sprintf("no path from %d to %d", req.StartNode, req.TargetNode), nil)
+	if !done[req.TargetNode] {
+		writeJSON(w, http.StatusOK, types.NewDijkstraResult(base, nil, 0, nil))
+		return
+	}
+
+	path := []int{req.TargetNode}
+	var details []map[string]interface{}
+	for id := req.TargetNode; id != req.StartNode; {
+		e := via[id]
+		details = append([]map[string]interface{}{{
+			"edge_id": e.ID,
+			"source":  e.Source,
+			"target":  e.Target,
+			"cost":    edgeWeight(e),
+		}}, details...)
+		id = e.Source
+		path = append([]int{id}, path...)
+	}
+	base.Message = fmt.Sprintf("found shortest path with cost %g", dist[req.TargetNode])
+	writeJSON(w, http.StatusOK, types.NewDijkstraResult(base, path, dist[req.TargetNode], details))
+}
+
+func (s *Server) handlePageRank(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MaxIterations int     `json:"max_iterations"`
+		Tolerance     float64 `json:"tolerance"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.MaxIterations <= 0 {
+		req.MaxIterations = 100
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.nodeIDs()
+	n := float64(len(ids))
+	scores := make(map[int]float64, len(ids))
+	for _, id := range ids {
+		scores[id] = 1 / n
+	}
+
+	adjacency := s.adjacency()
+	iterations := 0
+	converged := len(ids) == 0
+	for !converged && iterations < req.MaxIterations {
+		iterations++
+		dangling := 0.0
+		for _, id := range ids {
+			if len(adjacency[id]) == 0 {
+				dangling += scores[id]
+			}
+		}
+
+		next := make(map[int]float64, len(ids))
+		for _, id := range ids {
+			next[id] = (1-pageRankDamping)/n + pageRankDamping*dangling/n
+		}
+		for _, id := range ids {
+			out := adjacency[id]
+			for _, e := range out {
+				next[e.Target] += pageRankDamping * scores[id] / float64(len(out))
+			}
+		}
+
+		delta := 0.0
+		for _, id := range ids {
+			delta += math.Abs(next[id] - scores[id])
+		}
+		scores = next
+		converged = delta < req.Tolerance
+	}
+
+	base, _ := types.NewAlgorithmResult("pagerank", types.StatusCompleted,
+		fmt.Sprintf("pagerank finished after %d iterations", iterations), nil)
+	writeJSON(w, http.StatusOK, types.NewPageRankResult(base, scores, iterations, converged))
+}
+
+// adjacency returns outgoing edges per node in edge ID order; callers must hold s.mu
+func (s *Server) adjacency() map[int][]*types.GraphEdge {
+	adj := make(map[int][]*types.GraphEdge, len(s.nodes))
+	for _, id := range s.edgeIDs() {
+		e := s.edges[id]
+		adj[e.Source] = append(adj[e.Source], e)
+	}
+	return adj
+}
+
+// edgeWeight reads the "weight" property of an edge, defaulting to 1
+func edgeWeight(e *types.GraphEdge) float64 {
+	switch w := e.Properties["weight"].(type) {
+	case json.Number:
+		if f, err := w.Float64(); err == nil {
+			return f
+		}
+	case float64:
+		return w
+	case int:
+		return float64(w)
+	}
+	return 1
+}
+
+type distanceItem struct {
+	node int
+	dist float64
+}
+
+type distanceQueue []distanceItem
+
+func (q distanceQueue) Len() int            { return len(q) }
+func (q distanceQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q distanceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(x interface{}) { *q = append(*q, x.(distanceItem)) }
+func (q *distanceQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,796 @@
+// Package nendbtest provides an in-process NenDB server for hermetic testing.
+//
+// The server implements every endpoint used by the client package on top of an
+// in-memory graph, so code built on the driver can be tested against real HTTP
+// semantics without a live NenDB instance:
+//
+//	srv := nendbtest.NewServer()
+//	defer srv.Close()
+//
+//	c, err := client.NewClient(&client.ClientConfig{BaseURL: srv.URL})
+package nendbtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// Version is the server version reported by the fake /health endpoint
+const Version = "0.0.1-nendbtest"
+
+const (
+	// MaxBatchSize is the largest number of items accepted by a batch endpoint
+	MaxBatchSize = 1000
+	// DefaultPageSize is the page size of list endpoints without a limit
+	DefaultPageSize = 100
+	// MaxPageSize is the largest page size accepted by list endpoints
+	MaxPageSize = 1000
+)
+
+// QueryHandler answers a /query request. Returning a *StatusError controls
+// the HTTP status code sent to the client; any other error is reported as 400.
+type QueryHandler func(query string, params map[string]interface{}) (interface{}, error)
+
+// StatusError is an error with an HTTP status code and NenDB error code
+type StatusError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// Server is an in-memory NenDB server listening on a local loopback address
+type Server struct {
+	*httptest.Server
+
+	mu           sync.RWMutex
+	nodes        map[int]*types.GraphNode
+	edges        map[int]*types.GraphEdge
+	nextNodeID   int
+	nextEdgeID   int
+	queryHandler QueryHandler
+	failures     []int
+	requests     int
+
+	// txs maps open transaction IDs to their undo logs
+	txs        map[string][]func()
+	nextTxID   int
+	txDisabled bool
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when
+// finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		nodes:      make(map[int]*types.GraphNode),
+		edges:      make(map[int]*types.GraphEdge),
+		nextNodeID: 1,
+		nextEdgeID: 1,
+		txs:        make(map[string][]func()),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// SetQueryHandler replaces the built-in query engine. Passing nil restores it.
+func (s *Server) SetQueryHandler(h QueryHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queryHandler = h
+}
+
+// AddNode seeds the graph with a node and returns a copy of it
+func (s *Server) AddNode(labels []string, properties map[string]interface{}) *types.GraphNode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyNode(s.addNode(labels, properties))
+}
+
+// AddEdge seeds the graph with an edge and returns a copy of it. It returns an
+// error if either endpoint does not exist.
+func (s *Server) AddEdge(source, target int, edgeType string, properties map[string]interface{}) (*types.GraphEdge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	edge, err := s.addEdge(source, target, edgeType, properties)
+	if err != nil {
+		return nil, err
+	}
+	return copyEdge(edge), nil
+}
+
+// Node returns a copy of the node with the given ID, or nil if it does not exist
+func (s *Server) Node(id int) *types.GraphNode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if n, ok := s.nodes[id]; ok {
+		return copyNode(n)
+	}
+	return nil
+}
+
+// Edge returns a copy of the edge with the given ID, or nil if it does not exist
+func (s *Server) Edge(id int) *types.GraphEdge {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if e, ok := s.edges[id]; ok {
+		return copyEdge(e)
+	}
+	return nil
+}
+
+// Nodes returns copies of all nodes ordered by ID
+func (s *Server) Nodes() []*types.GraphNode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	nodes := make([]*types.GraphNode, 0, len(s.nodes))
+	for _, id := range s.nodeIDs() {
+		nodes = append(nodes, copyNode(s.nodes[id]))
+	}
+	return nodes
+}
+
+// Edges returns copies of all edges ordered by ID
+func (s *Server) Edges() []*types.GraphEdge {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	edges := make([]*types.GraphEdge, 0, len(s.edges))
+	for _, id := range s.edgeIDs() {
+		edges = append(edges, copyEdge(s.edges[id]))
+	}
+	return edges
+}
+
+// Reset removes all nodes, edges, open transactions and pending injected
+// failures and restarts ID assignment
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes = make(map[int]*types.GraphNode)
+	s.edges = make(map[int]*types.GraphEdge)
+	s.nextNodeID = 1
+	s.nextEdgeID = 1
+	s.failures = nil
+	s.txs = make(map[string][]func())
+}
+
+// FailNext makes the next n requests fail with the given HTTP status before
+// they reach any handler. The request body is read in full first, so clients
+// see a server that received the request and rejected it.
+func (s *Server) FailNext(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, status)
+	}
+}
+
+// Requests returns the number of requests received, including failed ones
+func (s *Server) Requests() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.requests
+}
+
+// ServeHTTP routes a request to the matching NenDB endpoint. Every response
+// carries an X-Request-Id header.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, status, fail := s.nextFailure()
+	w.Header().Set("X-Request-Id", fmt.Sprintf("nendbtest-%d", id))
+	if fail {
+		io.Copy(io.Discard, r.Body)
+		writeError(w, status, "INJECTED_FAILURE", fmt.Sprintf("nendbtest injected failure: %d", status))
+		return
+	}
+	if !s.checkTx(w, r) {
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(segments) == 1 && segments[0] == "health":
+		s.route(w, r, map[string]http.HandlerFunc{"GET": s.handleHealth})
+	case len(segments) == 1 && segments[0] == "statistics":
+		s.route(w, r, map[string]http.HandlerFunc{"GET": s.handleStatistics})
+	case len(segments) == 1 && segments[0] == "query":
+		s.route(w, r, map[string]http.HandlerFunc{"POST": s.handleQuery})
+	case len(segments) == 1 && segments[0] == "nodes":
+		s.route(w, r, map[string]http.HandlerFunc{"GET": s.handleListNodes, "POST": s.handleCreateNode})
+	case len(segments) == 2 && segments[0] == "nodes" && segments[1] == "batch":
+		s.route(w, r, map[string]http.HandlerFunc{"POST": s.handleCreateNodes})
+	case len(segments) == 2 && segments[0] == "nodes":
+		s.routeWithID(w, r, segments[1], map[string]func(http.ResponseWriter, *http.Request, int){
+			"GET":    s.handleGetNode,
+			"PUT":    s.handleUpdateNode,
+			"DELETE": s.handleDeleteNode,
+		})
+	case len(segments) == 1 && segments[0] == "edges":
+		s.route(w, r, map[string]http.HandlerFunc{"GET": s.handleListEdges, "POST": s.handleCreateEdge})
+	case len(segments) == 2 && segments[0] == "edges" && segments[1] == "batch":
+		s.route(w, r, map[string]http.HandlerFunc{"POST": s.handleCreateEdges})
+	case len(segments) == 2 && segments[0] == "edges":
+		s.routeWithID(w, r, segments[1], map[string]func(http.ResponseWriter, *http.Request, int){
+			"GET":    s.handleGetEdge,
+			"PUT":    s.handleUpdateEdge,
+			"DELETE": s.handleDeleteEdge,
+		})
+	case segments[0] == "transactions" && len(segments) <= 3:
+		s.serveTransactions(w, r, segments)
+	case len(segments) == 2 && segments[0] == "algorithms":
+		switch segments[1] {
+		case "bfs":
+			s.route(w, r, map[string]http.HandlerFunc{"POST": s.handleBFS})
+		case "dijkstra":
+			s.route(w, r, map[string]http.HandlerFunc{"POST": s.handleDijkstra})
+		case "pagerank":
+			s.route(w, r, map[string]http.HandlerFunc{"POST": s.handlePageRank})
+		default:
+			writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("unknown algorithm: %s", segments[1]))
+		}
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no route for %s", r.URL.Path))
+	}
+}
+
+// nextFailure counts the request, returning its sequence number, and pops
+// the next injected failure, if any
+func (s *Server) nextFailure() (int, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if len(s.failures) == 0 {
+		return s.requests, 0, false
+	}
+	status := s.failures[0]
+	s.failures = s.failures[1:]
+	return s.requests, status, true
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, handlers map[string]http.HandlerFunc) {
+	h, ok := handlers[r.Method]
+	if !ok {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", fmt.Sprintf("method %s not allowed on %s", r.Method, r.URL.Path))
+		return
+	}
+	h(w, r)
+}
+
+func (s *Server) routeWithID(w http.ResponseWriter, r *http.Request, rawID string, handlers map[string]func(http.ResponseWriter, *http.Request, int)) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil || id < 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", fmt.Sprintf("invalid ID: %s", rawID))
+		return
+	}
+	h, ok := handlers[r.Method]
+	if !ok {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", fmt.Sprintf("method %s not allowed on %s", r.Method, r.URL.Path))
+		return
+	}
+	h(w, r, id)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "healthy",
+		"service": "nendb",
+		"version": Version,
+	})
+}
+
+func (s *Server) handleStatistics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	labels := make(map[string]int)
+	edgeTypes := make(map[string]int)
+	propertyKeys := make(map[string]bool)
+	for _, n := range s.nodes {
+		for _, l := range n.Labels {
+			labels[l]++
+		}
+		for k := range n.Properties {
+			propertyKeys[k] = true
+		}
+	}
+	for _, e := range s.edges {
+		edgeTypes[e.Type]++
+		for k := range e.Properties {
+			propertyKeys[k] = true
+		}
+	}
+	keys := make([]string, 0, len(propertyKeys))
+	for k := range propertyKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"node_count":    len(s.nodes),
+		"edge_count":    len(s.edges),
+		"labels":        labels,
+		"edge_types":    edgeTypes,
+		"property_keys": keys,
+	})
+}
+
+type nodeRequest struct {
+	Labels     []string               `json:"labels"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type edgeRequest struct {
+	Source     int                    `json:"source"`
+	Target     int                    `json:"target"`
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+func (s *Server) handleListNodes(w http.ResponseWriter, r *http.Request) {
+	limit, after, ok := readPage(w, r)
+	if !ok {
+		return
+	}
+	label := r.URL.Query().Get("label")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nodes := []*types.GraphNode{}
+	next := ""
+	for _, id := range s.nodeIDs() {
+		n := s.nodes[id]
+		if id <= after || (label != "" && !hasLabel(n.Labels, label)) {
+			continue
+		}
+		if len(nodes) == limit {
+			next = encodeCursor(nodes[len(nodes)-1].ID)
+			break
+		}
+		nodes = append(nodes, copyNode(n))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"nodes": nodes, "next_cursor": next})
+}
+
+func (s *Server) handleCreateNode(w http.ResponseWriter, r *http.Request) {
+	var req nodeRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	node := copyNode(s.addNode(req.Labels, req.Properties))
+	s.recordUndo(r, s.undoCreateNode(node.ID))
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, node)
+}
+
+func (s *Server) handleCreateNodes(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Nodes []nodeRequest `json:"nodes"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if len(req.Nodes) > MaxBatchSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE", fmt.Sprintf("batch exceeds %d items", MaxBatchSize))
+		return
+	}
+
+	s.mu.Lock()
+	results := make([]map[string]interface{}, len(req.Nodes))
+	for i, n := range req.Nodes {
+		node := s.addNode(n.Labels, n.Properties)
+		s.recordUndo(r, s.undoCreateNode(node.ID))
+		results[i] = map[string]interface{}{"index": i, "id": node.ID}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+func (s *Server) handleGetNode(w http.ResponseWriter, r *http.Request, id int) {
+	node := s.Node(id)
+	if node == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("node %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, node)
+}
+
+func (s *Server) handleUpdateNode(w http.ResponseWriter, r *http.Request, id int) {
+	var req nodeRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	node, ok := s.nodes[id]
+	if ok {
+		s.recordUndo(r, s.restoreNode(copyNode(node)))
+		if req.Labels != nil {
+			node.Labels = append([]string{}, req.Labels...)
+		}
+		if req.Properties != nil {
+			node.Properties = copyProperties(req.Properties)
+		}
+		node = copyNode(node)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("node %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, node)
+}
+
+func (s *Server) handleDeleteNode(w http.ResponseWriter, r *http.Request, id int) {
+	s.mu.Lock()
+	node, ok := s.nodes[id]
+	if ok {
+		s.recordUndo(r, s.undoDeleteNode(node, s.removeNode(id)))
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("node %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"deleted": true, "id": id})
+}
+
+func (s *Server) handleListEdges(w http.ResponseWriter, r *http.Request) {
+	limit, after, ok := readPage(w, r)
+	if !ok {
+		return
+	}
+	edgeType := r.URL.Query().Get("type")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	edges := []*types.GraphEdge{}
+	next := ""
+	for _, id := range s.edgeIDs() {
+		e := s.edges[id]
+		if id <= after || (edgeType != "" && e.Type != edgeType) {
+			continue
+		}
+		if len(edges) == limit {
+			next = encodeCursor(edges[len(edges)-1].ID)
+			break
+		}
+		edges = append(edges, copyEdge(e))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"edges": edges, "next_cursor": next})
+}
+
+func (s *Server) handleCreateEdge(w http.ResponseWriter, r *http.Request) {
+	var req edgeRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Type == "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "edge type cannot be empty")
+		return
+	}
+
+	s.mu.Lock()
+	edge, err := s.addEdge(req.Source, req.Target, req.Type, req.Properties)
+	if err == nil {
+		s.recordUndo(r, s.undoCreateEdge(edge.ID))
+		edge = copyEdge(edge)
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, edge)
+}
+
+func (s *Server) handleCreateEdges(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Edges []edgeRequest `json:"edges"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if len(req.Edges) > MaxBatchSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE", fmt.Sprintf("batch exceeds %d items", MaxBatchSize))
+		return
+	}
+
+	s.mu.Lock()
+	results := make([]map[string]interface{}, len(req.Edges))
+	for i, e := range req.Edges {
+		if e.Type == "" {
+			results[i] = batchItemError(i, "VALIDATION_ERROR", "edge type cannot be empty")
+			continue
+		}
+		edge, err := s.addEdge(e.Source, e.Target, e.Type, e.Properties)
+		if err != nil {
+			results[i] = batchItemError(i, "NOT_FOUND", err.Error())
+			continue
+		}
+		s.recordUndo(r, s.undoCreateEdge(edge.ID))
+		results[i] = map[string]interface{}{"index": i, "id": edge.ID}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"results": results})
+}
+
+func (s *Server) handleGetEdge(w http.ResponseWriter, r *http.Request, id int) {
+	edge := s.Edge(id)
+	if edge == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("edge %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, edge)
+}
+
+func (s *Server) handleUpdateEdge(w http.ResponseWriter, r *http.Request, id int) {
+	var req edgeRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	edge, ok := s.edges[id]
+	if ok {
+		s.recordUndo(r, s.restoreEdge(copyEdge(edge)))
+		if req.Type != "" {
+			edge.Type = req.Type
+		}
+		if req.Properties != nil {
+			edge.Properties = copyProperties(req.Properties)
+		}
+		edge = copyEdge(edge)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("edge %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, edge)
+}
+
+func (s *Server) handleDeleteEdge(w http.ResponseWriter, r *http.Request, id int) {
+	s.mu.Lock()
+	edge, ok := s.edges[id]
+	if ok {
+		delete(s.edges, id)
+		s.recordUndo(r, s.restoreEdge(edge))
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("edge %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"deleted": true, "id": id})
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query  string                 `json:"query"`
+		Params map[string]interface{} `json:"params"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.RLock()
+	h := s.queryHandler
+	s.mu.RUnlock()
+
+	var result interface{}
+	var err error
+	if h != nil {
+		result, err = h(req.Query, req.Params)
+	} else {
+		start := time.Now()
+		var table *queryTable
+		if table, err = s.execQuery(req.Query, req.Params); err == nil {
+			table.Summary = map[string]interface{}{
+				"execution_time_ms": float64(time.Since(start)) / float64(time.Millisecond),
+			}
+			result = table
+		}
+	}
+	if err != nil {
+		if se, ok := err.(*StatusError); ok {
+			writeError(w, se.Status, se.Code, se.Message)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "QUERY_ERROR", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// addNode stores a new node; callers must hold s.mu
+func (s *Server) addNode(labels []string, properties map[string]interface{}) *types.GraphNode {
+	if labels == nil {
+		labels = []string{}
+	}
+	node := &types.GraphNode{
+		ID:         s.nextNodeID,
+		Labels:     append([]string{}, labels...),
+		Properties: copyProperties(properties),
+	}
+	s.nodes[node.ID] = node
+	s.nextNodeID++
+	return node
+}
+
+// removeNode deletes a node and its incident edges, returning the removed
+// edges; callers must hold s.mu
+func (s *Server) removeNode(id int) []*types.GraphEdge {
+	delete(s.nodes, id)
+	var removed []*types.GraphEdge
+	for edgeID, e := range s.edges {
+		if e.Source == id || e.Target == id {
+			removed = append(removed, e)
+			delete(s.edges, edgeID)
+		}
+	}
+	return removed
+}
+
+// addEdge stores a new edge; callers must hold s.mu
+func (s *Server) addEdge(source, target int, edgeType string, properties map[string]interface{}) (*types.GraphEdge, error) {
+	if _, ok := s.nodes[source]; !ok {
+		return nil, fmt.Errorf("source node %d not found", source)
+	}
+	if _, ok := s.nodes[target]; !ok {
+		return nil, fmt.Errorf("target node %d not found", target)
+	}
+	edge := &types.GraphEdge{
+		ID:         s.nextEdgeID,
+		Source:     source,
+		Target:     target,
+		Type:       edgeType,
+		Properties: copyProperties(properties),
+	}
+	s.edges[edge.ID] = edge
+	s.nextEdgeID++
+	return edge, nil
+}
+
+// nodeIDs returns all node IDs in ascending order; callers must hold s.mu
+func (s *Server) nodeIDs() []int {
+	ids := make([]int, 0, len(s.nodes))
+	for id := range s.nodes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// edgeIDs returns all edge IDs in ascending order; callers must hold s.mu
+func (s *Server) edgeIDs() []int {
+	ids := make([]int, 0, len(s.edges))
+	for id := range s.edges {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func copyProperties(properties map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(properties))
+	for k, v := range properties {
+		out[k] = v
+	}
+	return out
+}
+
+func copyNode(n *types.GraphNode) *types.GraphNode {
+	return &types.GraphNode{
+		ID:         n.ID,
+		Labels:     append([]string{}, n.Labels...),
+		Properties: copyProperties(n.Properties),
+	}
+}
+
+func copyEdge(e *types.GraphEdge) *types.GraphEdge {
+	return &types.GraphEdge{
+		ID:         e.ID,
+		Source:     e.Source,
+		Target:     e.Target,
+		Type:       e.Type,
+		Properties: copyProperties(e.Properties),
+	}
+}
+
+// readPage parses the limit and cursor query parameters of a list request,
+// writing a 400 response on failure. Listings resume after the returned ID.
+func readPage(w http.ResponseWriter, r *http.Request) (limit, after int, ok bool) {
+	q := r.URL.Query()
+	limit = DefaultPageSize
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > MaxPageSize {
+			writeError(w, http.StatusBadRequest, "INVALID_LIMIT", fmt.Sprintf("limit must be between 1 and %d", MaxPageSize))
+			return 0, 0, false
+		}
+		limit = n
+	}
+	if raw := q.Get("cursor"); raw != "" {
+		id, err := decodeCursor(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_CURSOR", fmt.Sprintf("invalid cursor: %s", raw))
+			return 0, 0, false
+		}
+		after = id
+	}
+	return limit, after, true
+}
+
+// encodeCursor returns an opaque cursor positioned after id
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("after:" + strconv.Itoa(id)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	rest, found := strings.CutPrefix(string(raw), "after:")
+	if !found {
+		return 0, fmt.Errorf("malformed cursor")
+	}
+	return strconv.Atoi(rest)
+}
+
+// readJSON decodes the request body into v, writing a 400 response on failure.
+// Numbers are kept as json.Number so large integers survive a round trip.
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func batchItemError(index int, code, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"index": index,
+		"error": map[string]interface{}{"code": code, "message": message},
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"code":    code,
+		"message": message,
+	})
+}
@@ -0,0 +1,216 @@
+package nendbtest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func newClient(t *testing.T, srv *nendbtest.Server) *client.NenDBClient {
+	t.Helper()
+	c, err := client.NewClient(&client.ClientConfig{
+		BaseURL:    srv.URL,
+		Timeout:    client.DefaultConfig().Timeout,
+		MaxRetries: 0,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return c
+}
+
+func TestServerHealthAndStatistics(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+
+	if err := c.Health(); err != nil {
+		t.Fatalf("Expected healthy server, got %v", err)
+	}
+
+	a := srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice"})
+	b := srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Bob"})
+	if _, err := srv.AddEdge(a.ID, b.ID, "KNOWS", nil); err != nil {
+		t.Fatalf("Failed to seed edge: %v", err)
+	}
+
+	stats, err := c.GetStatistics(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get statistics: %v", err)
+	}
+	if stats["node_count"] != float64(2) {
+		t.Errorf("Expected node_count 2, got %v", stats["node_count"])
+	}
+	if stats["edge_count"] != float64(1) {
+		t.Errorf("Expected edge_count 1, got %v", stats["edge_count"])
+	}
+}
+
+func TestServerNodeAndEdgeCRUD(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	alice, err := c.CreateNode(ctx, []string{"Person"}, map[string]interface{}{"name": "Alice"})
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	bob, err := c.CreateNode(ctx, []string{"Person"}, map[string]interface{}{"name": "Bob"})
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+
+	got, err := c.GetNode(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("Failed to get node: %v", err)
+	}
+	if got.Properties["name"] != "Alice" {
+		t.Errorf("Expected name 'Alice', got '%v'", got.Properties["name"])
+	}
+
+	updated, err := c.UpdateNode(ctx, alice.ID, []string{"Person", "Admin"}, map[string]interface{}{"name": "Alice", "age": 31})
+	if err != nil {
+		t.Fatalf("Failed to update node: %v", err)
+	}
+	if len(updated.Labels) != 2 {
+		t.Errorf("Expected 2 labels after update, got %v", updated.Labels)
+	}
+
+	edge, err := c.CreateEdge(ctx, alice.ID, bob.ID, "KNOWS", map[string]interface{}{"since": "2022"})
+	if err != nil {
+		t.Fatalf("Failed to create edge: %v", err)
+	}
+	if edge.Source != alice.ID || edge.Target != bob.ID {
+		t.Errorf("Expected edge %d->%d, got %d->%d", alice.ID, bob.ID, edge.Source, edge.Target)
+	}
+
+	if _, err := c.UpdateEdge(ctx, edge.ID, "FRIENDS", nil); err != nil {
+		t.Fatalf("Failed to update edge: %v", err)
+	}
+	if got := srv.Edge(edge.ID); got.Type != "FRIENDS" {
+		t.Errorf("Expected edge type 'FRIENDS', got '%s'", got.Type)
+	}
+
+	// Deleting a node removes its edges
+	if err := c.DeleteNode(ctx, alice.ID); err != nil {
+		t.Fatalf("Failed to delete node: %v", err)
+	}
+	if srv.Edge(edge.ID) != nil {
+		t.Error("Expected incident edge to be deleted with its node")
+	}
+	if _, err := c.GetNode(ctx, alice.ID); err == nil {
+		t.Error("Expected error getting deleted node, got nil")
+	}
+	if err := c.DeleteEdge(ctx, edge.ID); err == nil {
+		t.Error("Expected error deleting missing edge, got nil")
+	}
+}
+
+func TestServerRejectsEdgeToMissingNode(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+
+	n := srv.AddNode(nil, nil)
+	if _, err := c.CreateEdge(context.Background(), n.ID, 99, "KNOWS", nil); err == nil {
+		t.Error("Expected error creating edge to missing node, got nil")
+	}
+}
+
+func TestServerAlgorithms(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	// 1 -> 2 -> 3 (cost 2) and a direct 1 -> 3 (cost 5)
+	n1 := srv.AddNode(nil, nil)
+	n2 := srv.AddNode(nil, nil)
+	n3 := srv.AddNode(nil, nil)
+	srv.AddEdge(n1.ID, n2.ID, "ROAD", map[string]interface{}{"weight": 1})
+	srv.AddEdge(n2.ID, n3.ID, "ROAD", map[string]interface{}{"weight": 1})
+	srv.AddEdge(n1.ID, n3.ID, "ROAD", map[string]interface{}{"weight": 5})
+
+	bfs, err := c.RunBFS(ctx, n1.ID, n3.ID, 5)
+	if err != nil {
+		t.Fatalf("BFS failed: %v", err)
+	}
+	if len(bfs.Path) != 2 || bfs.Path[0] != n1.ID || bfs.Path[1] != n3.ID {
+		t.Errorf("Expected BFS path [%d %d], got %v", n1.ID, n3.ID, bfs.Path)
+	}
+
+	dijkstra, err := c.RunDijkstra(ctx, n1.ID, n3.ID)
+	if err != nil {
+		t.Fatalf("Dijkstra failed: %v", err)
+	}
+	if dijkstra.TotalCost != 2 {
+		t.Errorf("Expected total cost 2, got %f", dijkstra.TotalCost)
+	}
+	if len(dijkstra.ShortestPath) != 3 {
+		t.Errorf("Expected shortest path of 3 nodes, got %v", dijkstra.ShortestPath)
+	}
+
+	pagerank, err := c.RunPageRank(ctx, 100, 0.0001)
+	if err != nil {
+		t.Fatalf("PageRank failed: %v", err)
+	}
+	if !pagerank.Convergence {
+		t.Error("Expected PageRank to converge")
+	}
+	if pagerank.NodeScores[n3.ID] <= pagerank.NodeScores[n1.ID] {
+		t.Errorf("Expected node %d to outrank node %d, got %v", n3.ID, n1.ID, pagerank.NodeScores)
+	}
+}
+
+func TestServerQuery(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice"})
+	srv.AddNode([]string{"Company"}, map[string]interface{}{"name": "Nen"})
+	srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Bob"})
+
+	result, err := c.Query(ctx, "MATCH (n:Person) RETURN n LIMIT $limit", map[string]interface{}{"limit": 1})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if result.Len() != 1 {
+		t.Errorf("Expected 1 row, got %d", result.Len())
+	}
+
+	if _, err := c.Query(ctx, "MATCH (n) WHERE n.age > 1 RETURN n", nil); err == nil {
+		t.Error("Expected error for unsupported query, got nil")
+	}
+
+	// Custom handlers take over the query endpoint
+	srv.SetQueryHandler(func(query string, params map[string]interface{}) (interface{}, error) {
+		return nil, &nendbtest.StatusError{Status: http.StatusConflict, Code: "CONFLICT", Message: "locked"}
+	})
+	if _, err := c.Query(ctx, "MATCH (n) RETURN n", nil); err == nil {
+		t.Error("Expected error from custom query handler, got nil")
+	}
+}
+
+func TestServerFailNext(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+
+	srv.FailNext(1, http.StatusServiceUnavailable)
+	if err := c.Health(); err == nil {
+		t.Error("Expected injected failure, got nil")
+	}
+	if err := c.Health(); err != nil {
+		t.Errorf("Expected failures to be consumed, got %v", err)
+	}
+	// NewClient's health check counts as a request too
+	if got := srv.Requests(); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
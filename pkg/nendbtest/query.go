@@ -0,0 +1,131 @@
+package nendbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	nodePattern = regexp.MustCompile(`(?i)^MATCH\s+\((\w*)(?::(\w+))?\)\s+RETURN\s+(\w+)(?:\s+LIMIT\s+(\d+|\$\w+))?\s*;?$`)
+	edgePattern = regexp.MustCompile(`(?i)^MATCH\s+\(\w*\)-\[(\w*)(?::(\w+))?\]->\(\w*\)\s+RETURN\s+(\w+)(?:\s+LIMIT\s+(\d+|\$\w+))?\s*;?$`)
+)
+
+// queryTable is the response body of the built-in query engine
+type queryTable struct {
+	Columns []string               `json:"columns"`
+	Rows    [][]interface{}        `json:"rows"`
+	Summary map[string]interface{} `json:"summary,omitempty"`
+}
+
+// execQuery runs the built-in query engine, which understands two forms:
+//
+//	MATCH (n[:Label]) RETURN n [LIMIT k]
+//	MATCH ()-[r[:TYPE]]->() RETURN r [LIMIT k]
+func (s *Server) execQuery(query string, params map[string]interface{}) (*queryTable, error) {
+	query = strings.TrimSpace(query)
+
+	if m := nodePattern.FindStringSubmatch(query); m != nil {
+		limit, err := queryLimit(m[4], params)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkReturn(m[1], m[3]); err != nil {
+			return nil, err
+		}
+
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		rows := [][]interface{}{}
+		for _, id := range s.nodeIDs() {
+			if limit >= 0 && len(rows) >= limit {
+				break
+			}
+			n := s.nodes[id]
+			if m[2] != "" && !hasLabel(n.Labels, m[2]) {
+				continue
+			}
+			rows = append(rows, []interface{}{copyNode(n)})
+		}
+		return &queryTable{Columns: []string{m[3]}, Rows: rows}, nil
+	}
+
+	if m := edgePattern.FindStringSubmatch(query); m != nil {
+		limit, err := queryLimit(m[4], params)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkReturn(m[1], m[3]); err != nil {
+			return nil, err
+		}
+
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		rows := [][]interface{}{}
+		for _, id := range s.edgeIDs() {
+			if limit >= 0 && len(rows) >= limit {
+				break
+			}
+			e := s.edges[id]
+			if m[2] != "" && e.Type != m[2] {
+				continue
+			}
+			rows = append(rows, []interface{}{copyEdge(e)})
+		}
+		return &queryTable{Columns: []string{m[3]}, Rows: rows}, nil
+	}
+
+	return nil, &StatusError{
+		Status:  http.StatusBadRequest,
+		Code:    "UNSUPPORTED_QUERY",
+		Message: fmt.Sprintf("nendbtest cannot execute query: %s", query),
+	}
+}
+
+func checkReturn(variable, returned string) error {
+	if variable != returned {
+		return &StatusError{
+			Status:  http.StatusBadRequest,
+			Code:    "SYNTAX_ERROR",
+			Message: fmt.Sprintf("variable %q not defined", returned),
+		}
+	}
+	return nil
+}
+
+// queryLimit resolves a LIMIT literal or $parameter; -1 means no limit
+func queryLimit(raw string, params map[string]interface{}) (int, error) {
+	if raw == "" {
+		return -1, nil
+	}
+	if strings.HasPrefix(raw, "$") {
+		name := raw[1:]
+		switch v := params[name].(type) {
+		case json.Number:
+			n, err := strconv.Atoi(v.String())
+			if err == nil {
+				return n, nil
+			}
+		case float64:
+			return int(v), nil
+		}
+		return 0, &StatusError{
+			Status:  http.StatusBadRequest,
+			Code:    "PARAMETER_MISSING",
+			Message: fmt.Sprintf("expected integer parameter $%s", name),
+		}
+	}
+	return strconv.Atoi(raw)
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package nendbtest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nen-co/nendb-go/pkg/types"
+)
+
+// TransactionHeader carries the transaction ID on requests that belong to a
+// server-side transaction
+const TransactionHeader = "X-NenDB-Transaction"
+
+// SetTransactions enables or disables the /transactions endpoints. They are
+// enabled by default; disable them to test clients against servers without
+// transaction support.
+func (s *Server) SetTransactions(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txDisabled = !enabled
+}
+
+// Transactions returns the number of open transactions
+func (s *Server) Transactions() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.txs)
+}
+
+// serveTransactions routes /transactions requests
+func (s *Server) serveTransactions(w http.ResponseWriter, r *http.Request, segments []string) {
+	s.mu.RLock()
+	disabled := s.txDisabled
+	s.mu.RUnlock()
+	if disabled {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no route for %s", r.URL.Path))
+		return
+	}
+
+	switch {
+	case len(segments) == 1:
+		s.route(w, r, map[string]http.HandlerFunc{"POST": s.handleBeginTx})
+	case len(segments) == 2:
+		s.route(w, r, map[string]http.HandlerFunc{"DELETE": func(w http.ResponseWriter, r *http.Request) {
+			s.handleEndTx(w, segments[1], false)
+		}})
+	case len(segments) == 3 && segments[2] == "commit":
+		s.route(w, r, map[string]http.HandlerFunc{"POST": func(w http.ResponseWriter, r *http.Request) {
+			s.handleEndTx(w, segments[1], true)
+		}})
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no route for %s", r.URL.Path))
+	}
+}
+
+func (s *Server) handleBeginTx(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.nextTxID++
+	id := fmt.Sprintf("tx-%d", s.nextTxID)
+	s.txs[id] = nil
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"id": id})
+}
+
+// handleEndTx commits or rolls back a transaction. Rolling back replays the
+// undo log in reverse.
+func (s *Server) handleEndTx(w http.ResponseWriter, id string, commit bool) {
+	s.mu.Lock()
+	undo, ok := s.txs[id]
+	if ok {
+		delete(s.txs, id)
+		if !commit {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "TX_NOT_FOUND", fmt.Sprintf("transaction %s not found", id))
+		return
+	}
+	if commit {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"committed": true, "id": id})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"rolled_back": true, "id": id})
+}
+
+// checkTx rejects requests naming a transaction that is not open
+func (s *Server) checkTx(w http.ResponseWriter, r *http.Request) bool {
+	id := r.Header.Get(TransactionHeader)
+	if id == "" {
+		return true
+	}
+	s.mu.RLock()
+	_, ok := s.txs[id]
+	s.mu.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "TX_NOT_FOUND", fmt.Sprintf("transaction %s not found", id))
+	}
+	return ok
+}
+
+// recordUndo appends undo to the log of the request's transaction, if any.
+// Callers must hold s.mu.
+func (s *Server) recordUndo(r *http.Request, undo func()) {
+	id := r.Header.Get(TransactionHeader)
+	if _, ok := s.txs[id]; ok {
+		s.txs[id] = append(s.txs[id], undo)
+	}
+}
+
+// undoCreateNode returns an undo function removing a created node
+func (s *Server) undoCreateNode(id int) func() {
+	return func() { s.removeNode(id) }
+}
+
+// undoCreateEdge returns an undo function removing a created edge
+func (s *Server) undoCreateEdge(id int) func() {
+	return func() { delete(s.edges, id) }
+}
+
+// restoreNode returns an undo function putting back an updated node
+func (s *Server) restoreNode(prev *types.GraphNode) func() {
+	return func() { s.nodes[prev.ID] = prev }
+}
+
+// restoreEdge returns an undo function putting back an updated or deleted
+// edge
+func (s *Server) restoreEdge(prev *types.GraphEdge) func() {
+	return func() { s.edges[prev.ID] = prev }
+}
+
+// undoDeleteNode returns an undo function restoring a node and the edges
+// removed with it
+func (s *Server) undoDeleteNode(node *types.GraphNode, edges []*types.GraphEdge) func() {
+	return func() {
+		s.nodes[node.ID] = node
+		for _, e := range edges {
+			s.edges[e.ID] = e
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Condition is a boolean expression used by Where. Property references are
+// validated identifiers; compared values are always bound as parameters.
+type Condition interface {
+	render(b *Builder) string
+}
+
+type comparison struct {
+	property string
+	operator string
+	value    interface{}
+}
+
+func (c comparison) render(b *Builder) string {
+	if !b.checkProperty(c.property) {
+		return ""
+	}
+	return fmt.Sprintf("%s %s %s", c.property, c.operator, b.bind(c.value))
+}
+
+type nullCheck struct {
+	property string
+	isNull   bool
+}
+
+func (c nullCheck) render(b *Builder) string {
+	if !b.checkProperty(c.property) {
+		return ""
+	}
+	if c.isNull {
+		return c.property + " IS NULL"
+	}
+	return c.property + " IS NOT NULL"
+}
+
+type labelCheck struct {
+	variable string
+	label    string
+}
+
+func (c labelCheck) render(b *Builder) string {
+	if !b.checkIdent(c.variable, "variable") || !b.checkIdent(c.label, "label") {
+		return ""
+	}
+	return c.variable + ":" + c.label
+}
+
+// operand is a rendered condition joined to others by AND
+type operand struct {
+	text string
+	// nested is set for junctions, which need parentheses alongside other
+	// operands
+	nested bool
+}
+
+type junction struct {
+	operator   string
+	conditions []Condition
+}
+
+func (j junction) render(b *Builder) string {
+	if len(j.conditions) == 0 {
+		b.fail(j.operator+" requires at least one condition", nil)
+		return ""
+	}
+	if len(j.conditions) == 1 {
+		return renderCondition(b, j.conditions[0])
+	}
+	parts := make([]string, len(j.conditions))
+	for i, c := range j.conditions {
+		parts[i] = renderCondition(b, c)
+		if _, nested := c.(junction); nested {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+j.operator+" ")
+}
+
+type negation struct {
+	condition Condition
+}
+
+func (n negation) render(b *Builder) string {
+	return "NOT (" + renderCondition(b, n.condition) + ")"
+}
+
+// renderCondition renders c, recording an error if it is nil
+func renderCondition(b *Builder, c Condition) string {
+	if c == nil {
+		b.fail("Condition must not be nil", nil)
+		return ""
+	}
+	return c.render(b)
+}
+
+// Eq matches when property equals value
+func Eq(property string, value interface{}) Condition {
+	return comparison{property, "=", value}
+}
+
+// Neq matches when property differs from value
+func Neq(property string, value interface{}) Condition {
+	return comparison{property, "<>", value}
+}
+
+// Gt matches when property is greater than value
+func Gt(property string, value interface{}) Condition {
+	return comparison{property, ">", value}
+}
+
+// Gte matches when property is greater than or equal to value
+func Gte(property string, value interface{}) Condition {
+	return comparison{property, ">=", value}
+}
+
+// Lt matches when property is less than value
+func Lt(property string, value interface{}) Condition {
+	return comparison{property, "<", value}
+}
+
+// Lte matches when property is less than or equal to value
+func Lte(property string, value interface{}) Condition {
+	return comparison{property, "<=", value}
+}
+
+// In matches when property is one of values, which should be a slice
+func In(property string, values interface{}) Condition {
+	return comparison{property, "IN", values}
+}
+
+// Contains matches when the string property contains substr
+func Contains(property, substr string) Condition {
+	return comparison{property, "CONTAINS", substr}
+}
+
+// StartsWith matches when the string property starts with prefix
+func StartsWith(property, prefix string) Condition {
+	return comparison{property, "STARTS WITH", prefix}
+}
+
+// EndsWith matches when the string property ends with suffix
+func EndsWith(property, suffix string) Condition {
+	return comparison{property, "ENDS WITH", suffix}
+}
+
+// IsNull matches when property is not set
+func IsNull(property string) Condition {
+	return nullCheck{property, true}
+}
+
+// IsNotNull matches when property is set
+func IsNotNull(property string) Condition {
+	return nullCheck{property, false}
+}
+
+// HasLabel matches when the node bound to variable carries label
+func HasLabel(variable, label string) Condition {
+	return labelCheck{variable, label}
+}
+
+// And matches when every condition matches
+func And(conditions ...Condition) Condition {
+	return junction{"AND", conditions}
+}
+
+// Or matches when any condition matches
+func Or(conditions ...Condition) Condition {
+	return junction{"OR", conditions}
+}
+
+// Not negates a condition
+func Not(condition Condition) Condition {
+	return negation{condition}
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pattern is a node or path pattern used by MATCH, CREATE and MERGE
+type Pattern struct {
+	nodes []nodePattern
+	rels  []relPattern
+	// invalid records misuse of the pattern methods, reported when the
+	// pattern is added to a Builder
+	invalid string
+}
+
+type nodePattern struct {
+	variable   string
+	labels     []string
+	properties map[string]interface{}
+}
+
+type relPattern struct {
+	variable   string
+	relType    string
+	direction  direction
+	properties map[string]interface{}
+}
+
+type direction int
+
+const (
+	outgoing direction = iota
+	incoming
+	undirected
+)
+
+// Node returns a pattern matching a single node: (variable:Label1:Label2).
+// The variable may be empty.
+func Node(variable string, labels ...string) Pattern {
+	return Pattern{nodes: []nodePattern{{variable: variable, labels: labels}}}
+}
+
+// Props adds bound property constraints to the last node or relationship of
+// the pattern: (n:Person {name: $p0})
+func (p Pattern) Props(properties map[string]interface{}) Pattern {
+	p = p.clone()
+	if len(p.nodes) == 0 {
+		if p.invalid == "" {
+			p.invalid = "Props must follow a node or relationship"
+		}
+		return p
+	}
+	if len(p.rels) == len(p.nodes) {
+		p.rels[len(p.rels)-1].properties = properties
+	} else {
+		p.nodes[len(p.nodes)-1].properties = properties
+	}
+	return p
+}
+
+// Out extends the pattern with an outgoing relationship: ()-[variable:TYPE]->
+// Follow it with To to name the target node.
+func (p Pattern) Out(variable, relType string) Pattern {
+	return p.rel(variable, relType, outgoing)
+}
+
+// In extends the pattern with an incoming relationship: ()<-[variable:TYPE]-
+func (p Pattern) In(variable, relType string) Pattern {
+	return p.rel(variable, relType, incoming)
+}
+
+// Related extends the pattern with an undirected relationship: ()-[variable:TYPE]-
+func (p Pattern) Related(variable, relType string) Pattern {
+	return p.rel(variable, relType, undirected)
+}
+
+// To ends a relationship started by Out, In or Related at a node
+func (p Pattern) To(variable string, labels ...string) Pattern {
+	p = p.clone()
+	p.nodes = append(p.nodes, nodePattern{variable: variable, labels: labels})
+	return p
+}
+
+func (p Pattern) rel(variable, relType string, dir direction) Pattern {
+	p = p.clone()
+	if len(p.nodes) == 0 && p.invalid == "" {
+		p.invalid = "A relationship must start at a node"
+	}
+	p.rels = append(p.rels, relPattern{variable: variable, relType: relType, direction: dir})
+	return p
+}
+
+func (p Pattern) clone() Pattern {
+	return Pattern{
+		nodes:   append([]nodePattern{}, p.nodes...),
+		rels:    append([]relPattern{}, p.rels...),
+		invalid: p.invalid,
+	}
+}
+
+// render writes the pattern text, binding property values on b
+func (p Pattern) render(b *Builder) string {
+	if p.invalid != "" {
+		b.fail(p.invalid, nil)
+		return ""
+	}
+	if len(p.nodes) == 0 || len(p.rels) > len(p.nodes) {
+		b.fail("Relationships in a pattern must be separated by To", nil)
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, n := range p.nodes {
+		sb.WriteString(renderNode(b, n))
+		if i < len(p.rels) {
+			sb.WriteString(renderRel(b, p.rels[i]))
+		}
+	}
+	if len(p.rels) == len(p.nodes) {
+		// A dangling relationship matches any target node
+		sb.WriteString("()")
+	}
+	return sb.String()
+}
+
+func renderNode(b *Builder, n nodePattern) string {
+	var sb strings.Builder
+	sb.WriteString("(")
+	if n.variable != "" && b.checkIdent(n.variable, "variable") {
+		sb.WriteString(n.variable)
+	}
+	for _, l := range n.labels {
+		if b.checkIdent(l, "label") {
+			sb.WriteString(":" + l)
+		}
+	}
+	sb.WriteString(renderProps(b, n.properties))
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func renderRel(b *Builder, r relPattern) string {
+	inner := ""
+	if r.variable != "" && b.checkIdent(r.variable, "variable") {
+		inner = r.variable
+	}
+	if r.relType != "" && b.checkIdent(r.relType, "relationship type") {
+		inner += ":" + r.relType
+	}
+	inner += renderProps(b, r.properties)
+
+	switch r.direction {
+	case incoming:
+		return fmt.Sprintf("<-[%s]-", inner)
+	case undirected:
+		return fmt.Sprintf("-[%s]-", inner)
+	default:
+		return fmt.Sprintf("-[%s]->", inner)
+	}
+}
+
+func renderProps(b *Builder, props map[string]interface{}) string {
+	if len(props) == 0 {
+		return ""
+	}
+	keys := sortedKeys(props)
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !b.checkIdent(k, "property name") {
+			return ""
+		}
+		items = append(items, fmt.Sprintf("%s: %s", k, b.bind(props[k])))
+	}
+	return " {" + strings.Join(items, ", ") + "}"
+}
@@ -0,0 +1,288 @@
+// Package query builds Cypher-like query text for NenDBClient.Query.
+//
+// Values are never written into the query text. Every literal passed to the
+// builder is bound as a parameter, and identifiers such as variables, labels
+// and property names are validated, so user input cannot change the shape of
+// the query:
+//
+//	text, params, err := query.Match(query.Node("n", "Person")).
+//		Where(query.Gt("n.age", minAge)).
+//		Return("n").
+//		OrderBy("n.name").
+//		Limit(10).
+//		Build()
+//	result, err := c.Query(ctx, text, params)
+package query
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/errors"
+)
+
+var (
+	identPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	propertyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+	returnPattern   = regexp.MustCompile(`^(?:[A-Za-z_][A-Za-z0-9_]*\((?:\*|[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?)?\)|[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?)(?:\s+(?i:AS)\s+[A-Za-z_][A-Za-z0-9_]*)?$`)
+	orderPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?(?:\s+(?i:ASC|DESC))?$`)
+)
+
+// Builder accumulates query clauses and their bound parameters. The zero
+// value is an empty query; methods return the receiver for chaining.
+type Builder struct {
+	clauses []string
+	params  map[string]interface{}
+	err     error
+	// where holds the operands of the WHERE clause ending the query, so a
+	// following Where call can extend it
+	where []operand
+}
+
+// New returns an empty Builder
+func New() *Builder {
+	return &Builder{params: make(map[string]interface{})}
+}
+
+// Match starts a query with a MATCH clause
+func Match(patterns ...Pattern) *Builder {
+	return New().Match(patterns...)
+}
+
+// Create starts a query with a CREATE clause
+func Create(patterns ...Pattern) *Builder {
+	return New().Create(patterns...)
+}
+
+// Merge starts a query with a MERGE clause
+func Merge(pattern Pattern) *Builder {
+	return New().Merge(pattern)
+}
+
+// Match appends a MATCH clause
+func (b *Builder) Match(patterns ...Pattern) *Builder {
+	return b.patternClause("MATCH", patterns)
+}
+
+// OptionalMatch appends an OPTIONAL MATCH clause
+func (b *Builder) OptionalMatch(patterns ...Pattern) *Builder {
+	return b.patternClause("OPTIONAL MATCH", patterns)
+}
+
+// Create appends a CREATE clause
+func (b *Builder) Create(patterns ...Pattern) *Builder {
+	return b.patternClause("CREATE", patterns)
+}
+
+// Merge appends a MERGE clause
+func (b *Builder) Merge(pattern Pattern) *Builder {
+	return b.patternClause("MERGE", []Pattern{pattern})
+}
+
+// Where appends a WHERE clause. Multiple conditions are combined with AND, as
+// are the conditions of consecutive Where calls.
+func (b *Builder) Where(conditions ...Condition) *Builder {
+	if len(conditions) == 0 {
+		return b
+	}
+	where := b.where[:len(b.where):len(b.where)]
+	for _, c := range conditions {
+		_, nested := c.(junction)
+		where = append(where, operand{renderCondition(b, c), nested})
+	}
+
+	parts := make([]string, len(where))
+	for i, o := range where {
+		parts[i] = o.text
+		if o.nested && len(where) > 1 {
+			parts[i] = "(" + o.text + ")"
+		}
+	}
+	clause := "WHERE " + strings.Join(parts, " AND ")
+	if b.where != nil && b.err == nil {
+		b.clauses[len(b.clauses)-1] = clause
+	} else {
+		b.add(clause)
+	}
+	b.where = where
+	return b
+}
+
+// Set appends a SET clause assigning each property to a bound value
+func (b *Builder) Set(properties map[string]interface{}) *Builder {
+	keys := sortedKeys(properties)
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !b.checkProperty(k) {
+			return b
+		}
+		items = append(items, fmt.Sprintf("%s = %s", k, b.bind(properties[k])))
+	}
+	return b.add("SET " + strings.Join(items, ", "))
+}
+
+// Delete appends a DELETE clause, or DETACH DELETE when detach is true
+func (b *Builder) Delete(detach bool, variables ...string) *Builder {
+	for _, v := range variables {
+		if !b.checkIdent(v, "variable") {
+			return b
+		}
+	}
+	clause := "DELETE "
+	if detach {
+		clause = "DETACH DELETE "
+	}
+	return b.add(clause + strings.Join(variables, ", "))
+}
+
+// Return appends a RETURN clause. Items are variables, properties (n.name),
+// aggregate calls (count(n), count(*)) and may carry an alias (n.name AS name).
+func (b *Builder) Return(items ...string) *Builder {
+	return b.returnClause("RETURN ", items)
+}
+
+// ReturnDistinct appends a RETURN DISTINCT clause
+func (b *Builder) ReturnDistinct(items ...string) *Builder {
+	return b.returnClause("RETURN DISTINCT ", items)
+}
+
+// OrderBy appends an ORDER BY clause. Items are properties or variables with
+// an optional ASC or DESC suffix.
+func (b *Builder) OrderBy(items ...string) *Builder {
+	for _, item := range items {
+		if !orderPattern.MatchString(item) {
+			b.fail("Invalid ORDER BY item", item)
+			return b
+		}
+	}
+	return b.add("ORDER BY " + strings.Join(items, ", "))
+}
+
+// Skip appends a SKIP clause with a bound count
+func (b *Builder) Skip(n int) *Builder {
+	if n < 0 {
+		b.fail("SKIP must not be negative", n)
+		return b
+	}
+	return b.add("SKIP " + b.bind(n))
+}
+
+// Limit appends a LIMIT clause with a bound count
+func (b *Builder) Limit(n int) *Builder {
+	if n < 0 {
+		b.fail("LIMIT must not be negative", n)
+		return b
+	}
+	return b.add("LIMIT " + b.bind(n))
+}
+
+// Build returns the query text and its parameters, or the first error
+// recorded while building
+func (b *Builder) Build() (string, map[string]interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if len(b.clauses) == 0 {
+		return "", nil, errors.NewValidationError("Query is empty", nil)
+	}
+	params := make(map[string]interface{}, len(b.params))
+	for k, v := range b.params {
+		params[k] = v
+	}
+	return strings.Join(b.clauses, " "), params, nil
+}
+
+// String returns the query text, or an empty string if the query is invalid
+func (b *Builder) String() string {
+	text, _, _ := b.Build()
+	return text
+}
+
+// Run builds the query and executes it with c
+func (b *Builder) Run(ctx context.Context, c *client.NenDBClient) (*client.Result, error) {
+	text, params, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return c.Query(ctx, text, params)
+}
+
+func (b *Builder) add(clause string) *Builder {
+	if b.err == nil {
+		b.clauses = append(b.clauses, clause)
+	}
+	b.where = nil
+	return b
+}
+
+func (b *Builder) patternClause(keyword string, patterns []Pattern) *Builder {
+	if len(patterns) == 0 {
+		b.fail(keyword+" requires at least one pattern", nil)
+		return b
+	}
+	rendered := make([]string, len(patterns))
+	for i, p := range patterns {
+		rendered[i] = p.render(b)
+	}
+	return b.add(keyword + " " + strings.Join(rendered, ", "))
+}
+
+func (b *Builder) returnClause(keyword string, items []string) *Builder {
+	if len(items) == 0 {
+		b.fail("RETURN requires at least one item", nil)
+		return b
+	}
+	for _, item := range items {
+		if !returnPattern.MatchString(item) {
+			b.fail("Invalid RETURN item", item)
+			return b
+		}
+	}
+	return b.add(keyword + strings.Join(items, ", "))
+}
+
+// bind stores value as a new parameter and returns its placeholder
+func (b *Builder) bind(value interface{}) string {
+	if b.params == nil {
+		b.params = make(map[string]interface{})
+	}
+	name := fmt.Sprintf("p%d", len(b.params))
+	b.params[name] = value
+	return "$" + name
+}
+
+func (b *Builder) checkIdent(s, kind string) bool {
+	if !identPattern.MatchString(s) {
+		b.fail("Invalid "+kind, s)
+		return false
+	}
+	return true
+}
+
+func (b *Builder) checkProperty(s string) bool {
+	if !propertyPattern.MatchString(s) || !strings.Contains(s, ".") {
+		b.fail("Invalid property reference (expected variable.property)", s)
+		return false
+	}
+	return true
+}
+
+// fail records the first error; later clauses are ignored
+func (b *Builder) fail(message string, value interface{}) {
+	if b.err == nil {
+		b.err = errors.NewValidationError(message, map[string]interface{}{"value": value})
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
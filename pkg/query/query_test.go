@@ -0,0 +1,189 @@
+package query
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/nen-co/nendb-go/pkg/client"
+	"github.com/nen-co/nendb-go/pkg/nendbtest"
+)
+
+func TestBuildMatchWhereReturn(t *testing.T) {
+	text, params, err := Match(Node("n", "Person")).
+		Where(Gt("n.age", 30), Or(Eq("n.name", "Alice"), StartsWith("n.name", "B"))).
+		Return("n", "n.name AS name").
+		OrderBy("n.name DESC").
+		Skip(5).
+		Limit(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	want := "MATCH (n:Person) WHERE n.age > $p0 AND (n.name = $p1 OR n.name STARTS WITH $p2) " +
+		"RETURN n, n.name AS name ORDER BY n.name DESC SKIP $p3 LIMIT $p4"
+	if text != want {
+		t.Errorf("Unexpected query text:\n got: %s\nwant: %s", text, want)
+	}
+	wantParams := map[string]interface{}{"p0": 30, "p1": "Alice", "p2": "B", "p3": 5, "p4": 10}
+	if !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("Expected params %v, got %v", wantParams, params)
+	}
+}
+
+func TestRepeatedWhereCombinesConditions(t *testing.T) {
+	text, params, err := Match(Node("n", "Person")).
+		Where(Or(Eq("n.name", "Alice"), Eq("n.name", "Bob"))).
+		Where(Gt("n.age", 30)).
+		OptionalMatch(Node("n").Out("", "OWNS").To("m")).
+		Where(IsNotNull("m.name")).
+		Return("n", "m").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	want := "MATCH (n:Person) WHERE (n.name = $p0 OR n.name = $p1) AND n.age > $p2 " +
+		"OPTIONAL MATCH (n)-[:OWNS]->(m) WHERE m.name IS NOT NULL RETURN n, m"
+	if text != want {
+		t.Errorf("Unexpected query text:\n got: %s\nwant: %s", text, want)
+	}
+	if len(params) != 3 {
+		t.Errorf("Expected 3 params, got %v", params)
+	}
+}
+
+func TestBuildPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    string
+		params  int
+	}{
+		{
+			name:    "create with properties",
+			builder: Create(Node("n", "Person", "Admin").Props(map[string]interface{}{"name": "Alice", "age": 31})),
+			want:    "CREATE (n:Person:Admin {age: $p0, name: $p1})",
+			params:  2,
+		},
+		{
+			name:    "outgoing relationship",
+			builder: Match(Node("a", "Person").Out("r", "KNOWS").To("b")).Return("b"),
+			want:    "MATCH (a:Person)-[r:KNOWS]->(b) RETURN b",
+		},
+		{
+			name:    "incoming relationship with properties",
+			builder: Match(Node("a").In("", "FOLLOWS").Props(map[string]interface{}{"since": 2020}).To("b")).Return("count(b)"),
+			want:    "MATCH (a)<-[:FOLLOWS {since: $p0}]-(b) RETURN count(b)",
+			params:  1,
+		},
+		{
+			name:    "dangling relationship",
+			builder: Match(Node("").Related("r", "")).Return("r"),
+			want:    "MATCH ()-[r]-() RETURN r",
+		},
+		{
+			name: "merge and set",
+			builder: Merge(Node("n", "Person").Props(map[string]interface{}{"email": "a@example.com"})).
+				Set(map[string]interface{}{"n.active": true}),
+			want:   "MERGE (n:Person {email: $p0}) SET n.active = $p1",
+			params: 2,
+		},
+		{
+			name:    "detach delete",
+			builder: Match(Node("n")).Where(IsNull("n.name"), Not(HasLabel("n", "Keep"))).Delete(true, "n"),
+			want:    "MATCH (n) WHERE n.name IS NULL AND NOT (n:Keep) DETACH DELETE n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, params, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			if text != tt.want {
+				t.Errorf("Unexpected query text:\n got: %s\nwant: %s", text, tt.want)
+			}
+			if len(params) != tt.params {
+				t.Errorf("Expected %d params, got %v", tt.params, params)
+			}
+		})
+	}
+}
+
+func TestBuildNeverInlinesValues(t *testing.T) {
+	hostile := `x"}) DETACH DELETE n //`
+	text, params, err := Match(Node("n").Props(map[string]interface{}{"name": hostile})).
+		Where(Contains("n.bio", hostile)).
+		Return("n").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if text != "MATCH (n {name: $p0}) WHERE n.bio CONTAINS $p1 RETURN n" {
+		t.Errorf("Unexpected query text: %s", text)
+	}
+	if params["p0"] != hostile || params["p1"] != hostile {
+		t.Errorf("Expected hostile values to be bound, got %v", params)
+	}
+}
+
+func TestBuildRejectsInvalidIdentifiers(t *testing.T) {
+	tests := map[string]*Builder{
+		"label":             Match(Node("n", "Person) DETACH DELETE (m")),
+		"variable":          Match(Node("n n")),
+		"property name":     Create(Node("n").Props(map[string]interface{}{"a b": 1})),
+		"where property":    Match(Node("n")).Where(Eq("n.name = 'x' OR 1", 1)),
+		"return item":       Match(Node("n")).Return("n; DELETE n"),
+		"order item":        Match(Node("n")).Return("n").OrderBy("n.name; DELETE n"),
+		"negative limit":    Match(Node("n")).Return("n").Limit(-1),
+		"empty or":          Match(Node("n")).Where(Or()),
+		"empty query":       New(),
+		"double relation":   Match(Node("a").Out("", "X").Out("", "Y")),
+		"props on empty":    Match(Pattern{}.Props(map[string]interface{}{"a": 1})),
+		"props before node": Match(Pattern{}.In("r", "X").Props(map[string]interface{}{"a": 1}).To("b")),
+		"rel before node":   Match(Pattern{}.Out("r", "X").To("b")),
+		"nil where":         Match(Node("n")).Where(nil),
+		"nil in and":        Match(Node("n")).Where(And(IsNull("n.a"), nil)),
+		"nil not":           Match(Node("n")).Where(Not(nil)),
+	}
+
+	for name, b := range tests {
+		t.Run(name, func(t *testing.T) {
+			text, params, err := b.Build()
+			if err == nil {
+				t.Fatalf("Expected error, got query %q with params %v", text, params)
+			}
+		})
+	}
+}
+
+func TestRunAgainstServer(t *testing.T) {
+	srv := nendbtest.NewServer()
+	defer srv.Close()
+
+	srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Alice"})
+	srv.AddNode([]string{"Company"}, map[string]interface{}{"name": "Nen"})
+	srv.AddNode([]string{"Person"}, map[string]interface{}{"name": "Bob"})
+
+	config := client.DefaultConfig()
+	config.BaseURL = srv.URL
+	config.MaxRetries = 0
+	c, err := client.NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := Match(Node("n", "Person")).Return("n").Limit(1).Run(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Len() != 1 {
+		t.Errorf("Expected 1 row, got %d", result.Len())
+	}
+	if result.Summary.Query != "MATCH (n:Person) RETURN n LIMIT $p0" {
+		t.Errorf("Unexpected query in summary: %s", result.Summary.Query)
+	}
+}
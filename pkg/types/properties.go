@@ -0,0 +1,572 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropertyValue is a node or edge property value. Valid values are nil and
+// values of the following kinds:
+//
+//	KindBool      bool
+//	KindInt       signed and unsigned integers that fit in an int64
+//	KindFloat     float32, float64
+//	KindString    string
+//	KindBytes     []byte
+//	KindTime      time.Time
+//	KindDuration  time.Duration
+//	KindList      slices and arrays of valid values
+//	KindMap       maps with string keys and valid values
+//	KindPoint     GeoPoint
+//
+// Named types with one of these as their underlying type are accepted too.
+// Values decoded from the server use int64, float64, string, bool, []byte,
+// time.Time, time.Duration, GeoPoint, []interface{} and
+// map[string]interface{}.
+type PropertyValue = interface{}
+
+// PropertyMap is an alias of Properties
+type PropertyMap = Properties
+
+// PropertyKind identifies the type of a property value
+type PropertyKind int
+
+const (
+	KindInvalid PropertyKind = iota
+	KindNull
+	KindBool
+	KindInt
+	KindFloat
+	KindString
+	KindBytes
+	KindTime
+	KindDuration
+	KindList
+	KindMap
+	KindPoint
+)
+
+var kindNames = []string{"invalid", "null", "bool", "int", "float", "string", "bytes", "time", "duration", "list", "map", "point"}
+
+func (k PropertyKind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "PropertyKind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// MaxPropertyDepth limits how deeply lists and maps may be nested
+const MaxPropertyDepth = 32
+
+// GeoPoint is a WGS 84 coordinate in degrees
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Valid reports whether the latitude and longitude are in range
+func (p GeoPoint) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// Wire encodings of the kinds JSON has no type for. Each is sent as an object
+// with a single key, for example {"$time": "2024-05-01T12:00:00Z"}.
+const (
+	wireBytes    = "$bytes"
+	wireTime     = "$time"
+	wireDuration = "$duration"
+	wirePoint    = "$point"
+)
+
+// KindOf returns the kind of a property value, or KindInvalid if v cannot be
+// stored. Elements of lists and maps are not inspected; use
+// ValidatePropertyValue for that.
+func KindOf(v interface{}) PropertyKind {
+	switch val := v.(type) {
+	case nil:
+		return KindNull
+	case time.Time:
+		return KindTime
+	case time.Duration:
+		return KindDuration
+	case GeoPoint:
+		return KindPoint
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return KindInt
+		}
+		if _, err := val.Float64(); err == nil {
+			return KindFloat
+		}
+		return KindInvalid
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return KindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return KindInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return KindInvalid
+		}
+		return KindInt
+	case reflect.Float32, reflect.Float64:
+		return KindFloat
+	case reflect.String:
+		return KindString
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return KindBytes
+		}
+		return KindList
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			return KindMap
+		}
+	}
+	return KindInvalid
+}
+
+// IsValidPropertyValue checks if a value is a valid property value
+func IsValidPropertyValue(value interface{}) bool {
+	return ValidatePropertyValue(value) == nil
+}
+
+// PropertyError describes an invalid property value
+type PropertyError struct {
+	// Path locates the value, for example "address.city" or "tags[2]"
+	Path   string
+	Reason string
+}
+
+func (e *PropertyError) Error() string {
+	if e.Path == "" {
+		return "invalid property value: " + e.Reason
+	}
+	return fmt.Sprintf("invalid property %q: %s", e.Path, e.Reason)
+}
+
+// ValidatePropertyValue checks value and, for lists and maps, every value
+// they contain. Failures are reported as a *PropertyError.
+func ValidatePropertyValue(value interface{}) error {
+	return validateValue("", value, 0)
+}
+
+// ValidateProperties checks every property in properties. Failures are
+// reported as a *PropertyError.
+func ValidateProperties(properties map[string]interface{}) error {
+	for _, key := range sortedKeys(properties) {
+		if err := validateKey("", key); err != nil {
+			return err
+		}
+		if err := validateValue(key, properties[key], 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateKey(path, key string) error {
+	switch {
+	case key == "":
+		return &PropertyError{Path: path, Reason: "property names cannot be empty"}
+	case strings.HasPrefix(key, "$"):
+		return &PropertyError{Path: joinPath(path, key), Reason: "property names starting with $ are reserved"}
+	}
+	return nil
+}
+
+func validateValue(path string, value interface{}, depth int) error {
+	if depth > MaxPropertyDepth {
+		return &PropertyError{Path: path, Reason: fmt.Sprintf("nested more than %d levels deep", MaxPropertyDepth)}
+	}
+
+	switch KindOf(value) {
+	case KindInvalid:
+		return &PropertyError{Path: path, Reason: fmt.Sprintf("unsupported type %T", value)}
+	case KindFloat:
+		if f, _ := toFloat64(value); math.IsNaN(f) || math.IsInf(f, 0) {
+			return &PropertyError{Path: path, Reason: "NaN and infinite numbers cannot be stored"}
+		}
+	case KindPoint:
+		if !value.(GeoPoint).Valid() {
+			return &PropertyError{Path: path, Reason: "latitude or longitude out of range"}
+		}
+	case KindList:
+		rv := reflect.ValueOf(value)
+		for i := 0; i < rv.Len(); i++ {
+			if err := validateValue(fmt.Sprintf("%s[%d]", path, i), rv.Index(i).Interface(), depth+1); err != nil {
+				return err
+			}
+		}
+	case KindMap:
+		rv := reflect.ValueOf(value)
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			if err := validateKey(path, key.String()); err != nil {
+				return err
+			}
+			if err := validateValue(joinPath(path, key.String()), rv.MapIndex(key).Interface(), depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Properties holds the properties of a node or edge.
+//
+// Its JSON encoding is lossless: integers and floats keep their type and
+// full precision, and bytes, times, durations and geo points are sent as
+// single-key objects such as {"$time": "2024-05-01T12:00:00Z"} and decoded
+// back into their Go types.
+type Properties map[string]interface{}
+
+// MarshalJSON encodes the properties in the wire format
+func (p Properties) MarshalJSON() ([]byte, error) {
+	if p == nil {
+		return []byte("null"), nil
+	}
+	wire, err := encodeWire(map[string]interface{}(p))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON decodes properties from the wire format. Integers that do not
+// fit in an int64 are kept as json.Number.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*p = nil
+		return nil
+	}
+
+	props := make(Properties, len(raw))
+	for key, value := range raw {
+		props[key] = decodeWire(value)
+	}
+	*p = props
+	return nil
+}
+
+// encodeWire converts a property value into JSON-encodable values
+func encodeWire(value interface{}) (interface{}, error) {
+	switch val := value.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		if KindOf(val) == KindInvalid {
+			return nil, &PropertyError{Reason: fmt.Sprintf("invalid number %q", val)}
+		}
+		return val, nil
+	case time.Time:
+		return map[string]interface{}{wireTime: val.Format(time.RFC3339Nano)}, nil
+	case time.Duration:
+		return map[string]interface{}{wireDuration: val.String()}, nil
+	case GeoPoint:
+		return map[string]interface{}{wirePoint: val}, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch KindOf(value) {
+	case KindBool:
+		return rv.Bool(), nil
+	case KindInt:
+		if rv.CanInt() {
+			return json.Number(strconv.FormatInt(rv.Int(), 10)), nil
+		}
+		return json.Number(strconv.FormatUint(rv.Uint(), 10)), nil
+	case KindFloat:
+		return encodeFloat(rv)
+	case KindString:
+		return rv.String(), nil
+	case KindBytes:
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return map[string]interface{}{wireBytes: base64.StdEncoding.EncodeToString(b)}, nil
+	case KindList:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			item, err := encodeWire(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		return list, nil
+	case KindMap:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			item, err := encodeWire(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			m[iter.Key().String()] = item
+		}
+		return m, nil
+	}
+	return nil, &PropertyError{Reason: fmt.Sprintf("unsupported type %T", value)}
+}
+
+// encodeFloat writes a float so that it decodes as a float, adding ".0" to
+// integral values
+func encodeFloat(rv reflect.Value) (interface{}, error) {
+	f := rv.Float()
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, &PropertyError{Reason: "NaN and infinite numbers cannot be stored"}
+	}
+	bitSize := 64
+	if rv.Kind() == reflect.Float32 {
+		bitSize = 32
+	}
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return json.Number(s), nil
+}
+
+// decodeWire converts a value decoded with json.Decoder.UseNumber into a
+// property value
+func decodeWire(value interface{}) interface{} {
+	switch val := value.(type) {
+	case json.Number:
+		return decodeNumber(val)
+	case []interface{}:
+		for i, item := range val {
+			val[i] = decodeWire(item)
+		}
+		return val
+	case map[string]interface{}:
+		if typed, ok := decodeTyped(val); ok {
+			return typed
+		}
+		for key, item := range val {
+			val[key] = decodeWire(item)
+		}
+		return val
+	}
+	return value
+}
+
+// decodeNumber returns integers as int64 and other numbers as float64,
+// keeping n as is when either would lose precision
+func decodeNumber(n json.Number) interface{} {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return n
+}
+
+// decodeTyped recognises the single-key objects used for bytes, times,
+// durations and geo points
+func decodeTyped(m map[string]interface{}) (interface{}, bool) {
+	if len(m) != 1 {
+		return nil, false
+	}
+	for key, value := range m {
+		s, isString := value.(string)
+		switch key {
+		case wireBytes:
+			if b, err := base64.StdEncoding.DecodeString(s); isString && err == nil {
+				return b, true
+			}
+		case wireTime:
+			if t, err := time.Parse(time.RFC3339Nano, s); isString && err == nil {
+				return t, true
+			}
+		case wireDuration:
+			if d, err := time.ParseDuration(s); isString && err == nil {
+				return d, true
+			}
+		case wirePoint:
+			coords, _ := value.(map[string]interface{})
+			lat, latOK := toFloat64(coords["lat"])
+			lon, lonOK := toFloat64(coords["lon"])
+			if latOK && lonOK && len(coords) == 2 {
+				return GeoPoint{Lat: lat, Lon: lon}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Kind returns the kind of a property, or KindInvalid if it is not set
+func (p Properties) Kind(key string) PropertyKind {
+	value, ok := p[key]
+	if !ok {
+		return KindInvalid
+	}
+	return KindOf(value)
+}
+
+// Int64 returns an integer property. Floats with an integral value are
+// accepted.
+func (p Properties) Int64(key string) (int64, bool) {
+	return toInt64(p[key])
+}
+
+// Float64 returns a numeric property as a float64
+func (p Properties) Float64(key string) (float64, bool) {
+	return toFloat64(p[key])
+}
+
+// String returns a string property
+func (p Properties) String(key string) (string, bool) {
+	value, ok := p[key].(string)
+	return value, ok
+}
+
+// Bool returns a boolean property
+func (p Properties) Bool(key string) (bool, bool) {
+	value, ok := p[key].(bool)
+	return value, ok
+}
+
+// Bytes returns a bytes property
+func (p Properties) Bytes(key string) ([]byte, bool) {
+	value, ok := p[key].([]byte)
+	return value, ok
+}
+
+// Time returns a time property. RFC 3339 strings are accepted.
+func (p Properties) Time(key string) (time.Time, bool) {
+	switch value := p[key].(type) {
+	case time.Time:
+		return value, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// Duration returns a duration property. Strings such as "1h30m" are
+// accepted.
+func (p Properties) Duration(key string) (time.Duration, bool) {
+	switch value := p[key].(type) {
+	case time.Duration:
+		return value, true
+	case string:
+		d, err := time.ParseDuration(value)
+		return d, err == nil
+	}
+	return 0, false
+}
+
+// List returns a list property
+func (p Properties) List(key string) ([]interface{}, bool) {
+	value, ok := p[key].([]interface{})
+	return value, ok
+}
+
+// Map returns a map property
+func (p Properties) Map(key string) (Properties, bool) {
+	switch value := p[key].(type) {
+	case map[string]interface{}:
+		return Properties(value), true
+	case Properties:
+		return value, true
+	}
+	return nil, false
+}
+
+// Point returns a geo point property
+func (p Properties) Point(key string) (GeoPoint, bool) {
+	value, ok := p[key].(GeoPoint)
+	return value, ok
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	if n, ok := value.(json.Number); ok {
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	if _, ok := value.(time.Duration); ok {
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(rv.Uint()), rv.Uint() <= math.MaxInt64
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(f), true
+	}
+	return 0, false
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	if _, ok := value.(time.Duration); ok {
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
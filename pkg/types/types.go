@@ -2,7 +2,6 @@ package types
 
 import (
 	"fmt"
-	"reflect"
 )
 
 // AlgorithmStatus represents the status of algorithm execution
@@ -20,7 +19,7 @@ const (
 type GraphNode struct {
 	ID         int                    `json:"id"`
 	Labels     []string               `json:"labels"`
-	Properties map[string]interface{} `json:"properties"`
+	Properties Properties             `json:"properties"`
 }
 
 // NewGraphNode creates a new GraphNode with validation
@@ -62,7 +61,7 @@ type GraphEdge struct {
 	Source     int                    `json:"source"`
 	Target     int                    `json:"target"`
 	Type       string                 `json:"type"`
-	Properties map[string]interface{} `json:"properties"`
+	Properties Properties             `json:"properties"`
 }
 
 // NewGraphEdge creates a new GraphEdge with validation
@@ -112,6 +111,17 @@ func (e *GraphEdge) Validate() error {
 	return nil
 }
 
+// Path represents an alternating sequence of nodes and the edges joining them
+type Path struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Len returns the number of edges in the path
+func (p *Path) Len() int {
+	return len(p.Edges)
+}
+
 // AlgorithmResult represents the base result for algorithm execution
 type AlgorithmResult struct {
 	Algorithm string                 `json:"algorithm"`
@@ -229,21 +239,3 @@ func NewPageRankResult(base *AlgorithmResult, nodeScores map[int]float64, iterat
 // Type aliases for convenience
 type NodeID = int
 type EdgeID = int
-type PropertyValue = interface{}
-type PropertyMap = map[string]interface{}
-
-// IsValidPropertyValue checks if a value is a valid property value
-func IsValidPropertyValue(value interface{}) bool {
-	if value == nil {
-		return true
-	}
-	
-	switch reflect.TypeOf(value).Kind() {
-	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		 reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-		 reflect.Float32, reflect.Float64, reflect.Bool:
-		return true
-	default:
-		return false
-	}
-}
@@ -1,7 +1,11 @@
 package types
 
 import (
+	"encoding/json"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestGraphNodeValidation(t *testing.T) {
@@ -167,6 +171,14 @@ func TestPropertyValueValidation(t *testing.T) {
 		true,
 		false,
 		nil,
+		[]byte("raw"),
+		time.Now(),
+		90 * time.Second,
+		GeoPoint{Lat: 38.7, Lon: -9.1},
+		[]int{1, 2, 3},
+		[]interface{}{"a", 1, []string{"nested"}},
+		map[string]int{"a": 1},
+		map[string]interface{}{"address": map[string]interface{}{"city": "Lisbon"}},
 	}
 
 	for _, value := range validValues {
@@ -177,10 +189,15 @@ func TestPropertyValueValidation(t *testing.T) {
 
 	// Test invalid property values
 	invalidValues := []interface{}{
-		[]int{1, 2, 3},
-		map[string]int{"a": 1},
 		struct{}{},
 		func() {},
+		make(chan int),
+		map[int]string{1: "a"},
+		[]interface{}{1, func() {}},
+		map[string]interface{}{"$time": "reserved"},
+		uint64(math.MaxUint64),
+		math.NaN(),
+		GeoPoint{Lat: 91},
 	}
 
 	for _, value := range invalidValues {
@@ -189,3 +206,136 @@ func TestPropertyValueValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestPropertiesJSONRoundTrip(t *testing.T) {
+	props := Properties{
+		"id":       int64(math.MaxInt64),
+		"count":    7,
+		"ratio":    float64(2),
+		"small":    float32(0.1),
+		"name":     "Alice",
+		"active":   true,
+		"avatar":   []byte{0, 1, 255},
+		"born":     time.Date(1990, 4, 1, 12, 30, 0, 5, time.UTC),
+		"timeout":  90 * time.Second,
+		"home":     GeoPoint{Lat: 38.7223, Lon: -9.1393},
+		"tags":     []string{"a", "b"},
+		"address":  map[string]interface{}{"city": "Lisbon", "zip": 1000},
+		"nothing":  nil,
+		"verybig":  json.Number("18446744073709551616"),
+		"notation": 1e21,
+	}
+
+	data, err := json.Marshal(props)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded Properties
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	want := Properties{
+		"id":       int64(math.MaxInt64),
+		"count":    int64(7),
+		"ratio":    float64(2),
+		"small":    float64(0.1),
+		"name":     "Alice",
+		"active":   true,
+		"avatar":   []byte{0, 1, 255},
+		"born":     time.Date(1990, 4, 1, 12, 30, 0, 5, time.UTC),
+		"timeout":  90 * time.Second,
+		"home":     GeoPoint{Lat: 38.7223, Lon: -9.1393},
+		"tags":     []interface{}{"a", "b"},
+		"address":  map[string]interface{}{"city": "Lisbon", "zip": int64(1000)},
+		"nothing":  nil,
+		"verybig":  json.Number("18446744073709551616"),
+		"notation": 1e21,
+	}
+	for key, value := range want {
+		if !reflect.DeepEqual(decoded[key], value) {
+			t.Errorf("%s: expected %#v, got %#v", key, value, decoded[key])
+		}
+	}
+
+	if _, err := json.Marshal(Properties{"bad": math.Inf(1)}); err == nil {
+		t.Error("Expected error marshaling infinity, got nil")
+	}
+}
+
+func TestPropertiesAccessors(t *testing.T) {
+	var node GraphNode
+	err := json.Unmarshal([]byte(`{"id": 1, "labels": ["Person"], "properties": {
+		"age": 42, "height": 1.85, "name": "Alice", "admin": true,
+		"born": {"$time": "1990-04-01T12:30:00Z"}, "legacy_born": "1990-04-01T12:30:00Z",
+		"ttl": {"$duration": "1h30m0s"}, "home": {"$point": {"lat": 1.5, "lon": 2}},
+		"tags": ["a"], "address": {"city": "Lisbon"}, "key": {"$bytes": "AAH/"}
+	}}`), &node)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	p := node.Properties
+
+	if age, ok := p.Int64("age"); !ok || age != 42 {
+		t.Errorf("Int64(age) = %d, %v", age, ok)
+	}
+	if _, ok := p.Int64("height"); ok {
+		t.Error("Expected Int64(height) to fail for a fractional value")
+	}
+	if h, ok := p.Float64("height"); !ok || h != 1.85 {
+		t.Errorf("Float64(height) = %v, %v", h, ok)
+	}
+	if f, ok := p.Float64("age"); !ok || f != 42 {
+		t.Errorf("Float64(age) = %v, %v", f, ok)
+	}
+	if name, ok := p.String("name"); !ok || name != "Alice" {
+		t.Errorf("String(name) = %q, %v", name, ok)
+	}
+	if _, ok := p.String("age"); ok {
+		t.Error("Expected String(age) to fail")
+	}
+	if admin, ok := p.Bool("admin"); !ok || !admin {
+		t.Errorf("Bool(admin) = %v, %v", admin, ok)
+	}
+	want := time.Date(1990, 4, 1, 12, 30, 0, 0, time.UTC)
+	for _, key := range []string{"born", "legacy_born"} {
+		if born, ok := p.Time(key); !ok || !born.Equal(want) {
+			t.Errorf("Time(%s) = %v, %v", key, born, ok)
+		}
+	}
+	if ttl, ok := p.Duration("ttl"); !ok || ttl != 90*time.Minute {
+		t.Errorf("Duration(ttl) = %v, %v", ttl, ok)
+	}
+	if home, ok := p.Point("home"); !ok || home != (GeoPoint{Lat: 1.5, Lon: 2}) {
+		t.Errorf("Point(home) = %v, %v", home, ok)
+	}
+	if tags, ok := p.List("tags"); !ok || len(tags) != 1 {
+		t.Errorf("List(tags) = %v, %v", tags, ok)
+	}
+	if address, ok := p.Map("address"); !ok || address["city"] != "Lisbon" {
+		t.Errorf("Map(address) = %v, %v", address, ok)
+	}
+	if key, ok := p.Bytes("key"); !ok || string(key) != "\x00\x01\xff" {
+		t.Errorf("Bytes(key) = %v, %v", key, ok)
+	}
+	if _, ok := p.Int64("missing"); ok {
+		t.Error("Expected Int64(missing) to fail")
+	}
+	if kind := p.Kind("born"); kind != KindTime {
+		t.Errorf("Kind(born) = %v, expected time", kind)
+	}
+}
+
+func TestValidateProperties(t *testing.T) {
+	err := ValidateProperties(map[string]interface{}{
+		"name": "Alice",
+		"tags": []interface{}{"ok", make(chan int)},
+	})
+	propErr, ok := err.(*PropertyError)
+	if !ok || propErr.Path != "tags[1]" {
+		t.Errorf("Expected PropertyError at tags[1], got %v", err)
+	}
+	if err := ValidateProperties(map[string]interface{}{"": 1}); err == nil {
+		t.Error("Expected error for empty property name, got nil")
+	}
+}